@@ -0,0 +1,163 @@
+package log
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	config "github.com/tommzn/go-config"
+)
+
+// newBatchShipperFromConfig creates a batch shipper which uses passed function to ship a batch of records.
+// Settings are read from config with passed prefix, e.g. log.logzio.batchsize for prefix log.logzio.
+// Defaults for all settings are the same as for Logz.io and are used if there's no config.
+func newBatchShipperFromConfig(conf config.Config, prefix string, send func(records []Record) error) batchShipper {
+
+	batchSize := LOGZIO_BATCH_SIZE
+	shipmentStackSize := SHIPMENT_STACK_SIZE
+	messageStackSize := MESSAGE_STACK_SIZE
+	shipmentTimeout := SHIPMENT_WAIT_TIMEOUT
+	messageReadTimeout := MESSAGE_READ_TIMEOUT
+	if conf != nil {
+		batchSize = *conf.GetAsInt(prefix+".batchsize", config.AsIntPtr(batchSize))
+		shipmentStackSize = *conf.GetAsInt(prefix+".shipmentstacksize", config.AsIntPtr(shipmentStackSize))
+		messageStackSize = *conf.GetAsInt(prefix+".messagestacksize", config.AsIntPtr(messageStackSize))
+		shipmentTimeout = *conf.GetAsDuration(prefix+".shipmenttimeout", config.AsDurationPtr(shipmentTimeout))
+		messageReadTimeout = *conf.GetAsDuration(prefix+".messagereadtimeout", config.AsDurationPtr(messageReadTimeout))
+	}
+
+	shipper := batchShipper{
+		batchSize:             batchSize,
+		shipmentStack:         make(chan bool, shipmentStackSize),
+		messageStack:          make(chan Record, messageStackSize),
+		obtainShipmentTimeout: shipmentTimeout,
+		messageReadTimeout:    messageReadTimeout,
+		send:                  send,
+	}
+	shipper.initShipmentStack()
+	return shipper
+}
+
+// Ship will add passed records to an internal queue and starts shipment if
+// number of buffered records exceeds defined batch size.
+func (shipper *batchShipper) Ship(ctx context.Context, records []Record) error {
+
+	for _, record := range records {
+		shipper.messageStack <- record
+	}
+
+	if len(shipper.messageStack) <= shipper.batchSize {
+		return nil
+	}
+
+	if !shipper.obtainShipment() {
+		return nil
+	}
+
+	go func() {
+
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		shipper.shipBatch(wg)
+
+		wg.Wait()
+		shipper.releaseShipment()
+	}()
+	return nil
+}
+
+// initShipmentStack fills the shipment stack with all slots.
+func (shipper *batchShipper) initShipmentStack() {
+	for len(shipper.shipmentStack) < cap(shipper.shipmentStack) {
+		shipper.shipmentStack <- true
+	}
+}
+
+// Flush will deliver all messages from internal channel.
+func (shipper *batchShipper) Flush() error {
+
+	wg := &sync.WaitGroup{}
+	for len(shipper.messageStack) > 0 {
+		wg.Add(1)
+		shipper.shipBatch(wg)
+		wg.Wait()
+	}
+	return nil
+}
+
+// ObtainShipment will try to get a slot for shipment from shipment stack.
+// It will return with false if obtainShipmentTimeout exceeds.
+func (shipper *batchShipper) obtainShipment() bool {
+
+	timeout := time.NewTimer(shipper.obtainShipmentTimeout)
+	select {
+	case <-shipper.shipmentStack:
+		return true
+	case <-timeout.C:
+		return false
+	}
+}
+
+// ReleaseShipment will return a used slot to the shipment stack.
+func (shipper *batchShipper) releaseShipment() {
+
+	if len(shipper.shipmentStack) < cap(shipper.shipmentStack) {
+		shipper.shipmentStack <- true
+	}
+}
+
+// ShipBatch will read number of messages defined by batch size from internal channel
+// and start shipment for all of them.
+func (shipper *batchShipper) shipBatch(wg *sync.WaitGroup) {
+
+	messages := shipper.readMessages()
+	shipper.shipMessages(wg, messages)
+}
+
+// ReadMessages will try to read number of messages defined by batch size from internal buffer.
+// If it exceeds messages read timeout it will return messages it reads up to this point in time.
+func (shipper *batchShipper) readMessages() []Record {
+
+	var messages []Record
+	timeout := time.NewTimer(shipper.messageReadTimeout)
+	for len(messages) < shipper.batchSize {
+		select {
+		case message := <-shipper.messageStack:
+			messages = append(messages, message)
+		case <-timeout.C:
+			return messages
+		}
+	}
+	return messages
+}
+
+// ShipMessages passes given records to the send function of this shipper.
+// Nothing is sent for an empty batch, e.g. if another shipment read all messages before.
+func (shipper *batchShipper) shipMessages(wg *sync.WaitGroup, messages []Record) {
+
+	defer wg.Done()
+
+	if len(messages) == 0 {
+		return
+	}
+	if err := shipper.send(messages); err != nil {
+		shipper.shipmentFailed(messages, err)
+	}
+}
+
+// OnError registers a handler which is called with records of each batch which could not be shipped.
+// Without a handler errors are written to STDERR.
+func (shipper *batchShipper) OnError(handler func(records []Record, err error)) {
+	shipper.errorHandler = handler
+}
+
+// shipmentFailed passes records of a failed batch to the error handler.
+func (shipper *batchShipper) shipmentFailed(records []Record, err error) {
+
+	if shipper.errorHandler != nil {
+		shipper.errorHandler(records, err)
+		return
+	}
+	log.Println(err)
+}
@@ -0,0 +1,113 @@
+package log
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	config "github.com/tommzn/go-config"
+)
+
+// ENV_GOOGLE_CLOUD_PROJECT is the environment variable used to get the project id for traces.
+const ENV_GOOGLE_CLOUD_PROJECT = "GOOGLE_CLOUD_PROJECT"
+
+// Special JSON fields recognized by Google Cloud Logging.
+const (
+	cloudLoggingLabelsKey         = "logging.googleapis.com/labels"
+	cloudLoggingTraceKey          = "logging.googleapis.com/trace"
+	cloudLoggingSpanIdKey         = "logging.googleapis.com/spanId"
+	cloudLoggingSourceLocationKey = "logging.googleapis.com/sourceLocation"
+)
+
+// newCloudLoggingFormatter returns a new CloudLoggingFormatter. Project id is read from
+// environment variable GOOGLE_CLOUD_PROJECT and can be set by config: log.formatter.projectid
+func newCloudLoggingFormatter(conf config.Config) LogFormatter {
+
+	projectId := os.Getenv(ENV_GOOGLE_CLOUD_PROJECT)
+	if conf != nil {
+		projectId = *conf.Get("log.formatter.projectid", config.AsStringPtr(projectId))
+	}
+	return &CloudLoggingFormatter{projectId: projectId}
+}
+
+// CloudLoggingSeverity returns the Cloud Logging severity for passed log level.
+// Status is mapped to NOTICE, because it's used for normal but significant events.
+func CloudLoggingSeverity(logLevel LogLevel) string {
+
+	switch logLevel {
+	case Status:
+		return "NOTICE"
+	case Error:
+		return "ERROR"
+	case Info:
+		return "INFO"
+	case Debug:
+		return "DEBUG"
+	default:
+		return "DEFAULT"
+	}
+}
+
+// setTimestampFormat defines how the time of a record is rendered.
+func (formatter *CloudLoggingFormatter) setTimestampFormat(timestampFormat TimestampFormat) {
+	formatter.timestamp = timestampFormat
+}
+
+// setCollisionPolicy defines how context values with reserved keys are handled.
+func (formatter *CloudLoggingFormatter) setCollisionPolicy(policy CollisionPolicy) {
+	formatter.collisions = policy
+}
+
+// Format creates a structured JSON log entry for passed record. Context values are added as labels,
+// fields are added to the payload with their native types. Trace and span id from log context
+// and the caller of a record are mapped to their special fields. Fields with the key of a special
+// field, e.g. severity or message, are handled according to the collision policy.
+func (formatter *CloudLoggingFormatter) Format(record Record) ([]byte, error) {
+
+	entry := make(map[string]interface{})
+	labels := make(map[string]string)
+	for key, value := range record.Context.values {
+		labels[key] = value
+	}
+	for _, field := range record.Context.fields {
+		entry[field.Key] = field.jsonValue()
+	}
+	delete(labels, LogCtxTraceId)
+	delete(labels, LogCtxSpanId)
+	delete(entry, LogCtxTraceId)
+	delete(entry, LogCtxSpanId)
+
+	reserved := map[string]interface{}{
+		"severity": CloudLoggingSeverity(record.Level),
+		"message":  record.Message,
+	}
+	if !record.Time.IsZero() {
+		reserved["time"] = formatter.timestamp.value(record.Time.UTC(), time.RFC3339Nano)
+	}
+	if len(labels) > 0 {
+		reserved[cloudLoggingLabelsKey] = labels
+	}
+	if traceId, ok := record.Context.lookup(LogCtxTraceId); ok && traceId != "" {
+		reserved[cloudLoggingTraceKey] = formatter.traceName(traceId)
+	}
+	if spanId, ok := record.Context.lookup(LogCtxSpanId); ok && spanId != "" {
+		reserved[cloudLoggingSpanIdKey] = spanId
+	}
+	if record.Caller.IsDefined() {
+		reserved[cloudLoggingSourceLocationKey] = map[string]string{
+			"file":     record.Caller.File,
+			"line":     strconv.Itoa(record.Caller.Line),
+			"function": record.Caller.Function,
+		}
+	}
+	formatter.collisions.merge(entry, reserved)
+	return marshalWithFallback(entry), nil
+}
+
+// traceName returns the full resource name of a trace, if a project id is available.
+func (formatter *CloudLoggingFormatter) traceName(traceId string) string {
+	if formatter.projectId == "" {
+		return traceId
+	}
+	return "projects/" + formatter.projectId + "/traces/" + traceId
+}
@@ -0,0 +1,90 @@
+package log
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CloudLoggingFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestCloudLoggingFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(CloudLoggingFormatterTestSuite))
+}
+
+func (suite *CloudLoggingFormatterTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/cloudlogging.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&CloudLoggingFormatter{}, logger.(*LogHandler).formatter)
+	suite.Equal("my-project", logger.(*LogHandler).formatter.(*CloudLoggingFormatter).projectId)
+
+	os.Setenv(ENV_GOOGLE_CLOUD_PROJECT, "env-project")
+	suite.Equal("env-project", newCloudLoggingFormatter(nil).(*CloudLoggingFormatter).projectId)
+	os.Unsetenv(ENV_GOOGLE_CLOUD_PROJECT)
+}
+
+func (suite *CloudLoggingFormatterTestSuite) TestSeverity() {
+
+	suite.Equal("NOTICE", CloudLoggingSeverity(Status))
+	suite.Equal("ERROR", CloudLoggingSeverity(Error))
+	suite.Equal("INFO", CloudLoggingSeverity(Info))
+	suite.Equal("DEBUG", CloudLoggingSeverity(Debug))
+	suite.Equal("DEFAULT", CloudLoggingSeverity(None))
+}
+
+func (suite *CloudLoggingFormatterTestSuite) TestFormat() {
+
+	formatter := &CloudLoggingFormatter{projectId: "my-project"}
+	logContext := newLogContext(map[string]string{
+		LogCtxNamespace: "billing",
+		LogCtxRequestId: "req-1",
+		LogCtxTraceId:   "4bf92f3577b34da6a3ce929d0e0e4736",
+	}).AppendFields(Int("count", 3), String(LogCtxSpanId, "00f067aa0ba902b7"))
+	record := Record{
+		Time:    time.Date(2021, 5, 30, 12, 8, 47, 123456789, time.UTC),
+		Level:   Status,
+		Message: "Test Message",
+		Context: logContext,
+		Caller:  Caller{Function: "main.run", File: "main.go", Line: 12},
+	}
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	expected := `{
+		"severity": "NOTICE",
+		"message": "Test Message",
+		"time": "2021-05-30T12:08:47.123456789Z",
+		"count": 3,
+		"logging.googleapis.com/labels": {"namespace": "billing", "requestid": "req-1"},
+		"logging.googleapis.com/trace": "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736",
+		"logging.googleapis.com/spanId": "00f067aa0ba902b7",
+		"logging.googleapis.com/sourceLocation": {"file": "main.go", "line": "12", "function": "main.run"}
+	}`
+	suite.JSONEq(expected, string(logMessage))
+}
+
+func (suite *CloudLoggingFormatterTestSuite) TestFormatWithoutProject() {
+
+	formatter := &CloudLoggingFormatter{}
+	record := Record{
+		Time:    time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC),
+		Level:   Debug,
+		Message: "Test Message",
+		Context: newLogContext(map[string]string{LogCtxTraceId: "trace-1"}),
+	}
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	expected := `{
+		"severity": "DEBUG",
+		"message": "Test Message",
+		"time": "2021-05-30T12:08:47Z",
+		"logging.googleapis.com/trace": "trace-1"
+	}`
+	suite.JSONEq(expected, string(logMessage))
+}
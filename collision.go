@@ -0,0 +1,131 @@
+package log
+
+import (
+	"log"
+	"sort"
+	"strings"
+
+	config "github.com/tommzn/go-config"
+)
+
+// COLLISION_PREFIX is prepended to keys of context values which collide with reserved keys,
+// if collision policy is CollisionRename.
+const COLLISION_PREFIX = "fields."
+
+// CollisionPolicyByName returns the collision policy with passed name, which is one of rename,
+// reject or override. CollisionRename is returned for all other names.
+func CollisionPolicyByName(name string) CollisionPolicy {
+
+	switch strings.ToLower(name) {
+	case "reject":
+		return CollisionReject
+	case "override":
+		return CollisionOverride
+	default:
+		return CollisionRename
+	}
+}
+
+// collisionPolicyFromConfig returns the collision policy defined by config: log.formatter.collision
+// Returns false if there's no collision policy defined.
+func collisionPolicyFromConfig(conf config.Config) (CollisionPolicy, bool) {
+
+	if policyName := conf.Get("log.formatter.collision", nil); policyName != nil {
+		return CollisionPolicyByName(*policyName), true
+	}
+	return CollisionRename, false
+}
+
+// merge adds passed reserved values of a formatter to given document, which contains context values
+// and fields of a record. If a key of a reserved value is used by a context value, too, the collision
+// is resolved according to this policy.
+func (policy CollisionPolicy) merge(document map[string]interface{}, reserved map[string]interface{}) {
+
+	for key, value := range reserved {
+
+		contextValue, collides := document[key]
+		if !collides {
+			document[key] = value
+			continue
+		}
+
+		switch policy {
+		case CollisionOverride:
+			continue
+		case CollisionReject:
+			log.Printf("Context key %q is reserved, value %v has been dropped.", key, contextValue)
+		default:
+			document[COLLISION_PREFIX+key] = contextValue
+		}
+		document[key] = value
+	}
+}
+
+// mergeNested works like merge for documents with keys which are paths of nested objects, separated by dots.
+// A context value collides with a reserved value as well if one of their keys is a parent of the other,
+// e.g. service.name.x and service.name, because a value can't be an object and a scalar at the same time.
+func (policy CollisionPolicy) mergeNested(document map[string]interface{}, reserved map[string]interface{}) {
+
+	collisions := []string{}
+	for key := range document {
+		for reservedKey := range reserved {
+			if isParentKey(key, reservedKey) || isParentKey(reservedKey, key) {
+				collisions = append(collisions, key)
+				break
+			}
+		}
+	}
+	sort.Strings(collisions)
+
+	for _, key := range collisions {
+		contextValue := document[key]
+		switch policy {
+		case CollisionOverride:
+			for reservedKey := range reserved {
+				if isParentKey(key, reservedKey) || isParentKey(reservedKey, key) {
+					delete(reserved, reservedKey)
+				}
+			}
+			continue
+		case CollisionReject:
+			log.Printf("Context key %q collides with a reserved key, value %v has been dropped.", key, contextValue)
+		default:
+			document[COLLISION_PREFIX+key] = contextValue
+		}
+		delete(document, key)
+	}
+	policy.merge(document, reserved)
+}
+
+// resolveParents handles context values with a key which is a parent of another key in passed document,
+// e.g. user and user.id, because a value can't be an object and a scalar at the same time. The nested value
+// is kept and the value of the parent key is renamed for CollisionRename. For all other policies it's dropped,
+// because there's no reserved value which could be overridden, and written to STDERR.
+func (policy CollisionPolicy) resolveParents(document map[string]interface{}) {
+
+	parents := []string{}
+	for parent := range document {
+		for key := range document {
+			if isParentKey(parent, key) {
+				parents = append(parents, parent)
+				break
+			}
+		}
+	}
+	sort.Strings(parents)
+
+	for _, key := range parents {
+		contextValue := document[key]
+		delete(document, key)
+		if policy == CollisionRename {
+			document[COLLISION_PREFIX+key] = contextValue
+		} else {
+			log.Printf("Context key %q is a parent of another context key, value %v has been dropped.", key, contextValue)
+		}
+	}
+}
+
+// isParentKey returns true if passed parent is a path to an object containing passed key, e.g. service for service.name.
+func isParentKey(parent, key string) bool {
+	return strings.HasPrefix(key, parent+".")
+}
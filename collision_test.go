@@ -0,0 +1,189 @@
+package log
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CollisionPolicyTestSuite struct {
+	suite.Suite
+}
+
+func TestCollisionPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(CollisionPolicyTestSuite))
+}
+
+func (suite *CollisionPolicyTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/collision.yml")
+	policy, ok := collisionPolicyFromConfig(conf)
+	suite.True(ok)
+	suite.Equal(CollisionReject, policy)
+
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.Equal(CollisionReject, logger.(*LogHandler).formatter.(*LogzioJsonFormatter).collisions)
+
+	_, ok = collisionPolicyFromConfig(loadConfigFromFile("config/timestamp.yml"))
+	suite.False(ok)
+}
+
+func (suite *CollisionPolicyTestSuite) TestPolicyByName() {
+
+	suite.Equal(CollisionRename, CollisionPolicyByName("rename"))
+	suite.Equal(CollisionReject, CollisionPolicyByName("Reject"))
+	suite.Equal(CollisionOverride, CollisionPolicyByName("override"))
+	suite.Equal(CollisionRename, CollisionPolicyByName("xxx"))
+}
+
+func (suite *CollisionPolicyTestSuite) TestMerge() {
+
+	reserved := map[string]interface{}{LogCtxMessage: "Test Message", LogCtxLogLevel: "Info"}
+
+	document := map[string]interface{}{LogCtxMessage: "user message", "count": 3}
+	CollisionRename.merge(document, reserved)
+	suite.Equal(map[string]interface{}{
+		LogCtxMessage:                    "Test Message",
+		LogCtxLogLevel:                   "Info",
+		COLLISION_PREFIX + LogCtxMessage: "user message",
+		"count":                          3,
+	}, document)
+
+	document = map[string]interface{}{LogCtxMessage: "user message", "count": 3}
+	CollisionReject.merge(document, reserved)
+	suite.Equal(map[string]interface{}{LogCtxMessage: "Test Message", LogCtxLogLevel: "Info", "count": 3}, document)
+
+	document = map[string]interface{}{LogCtxMessage: "user message", "count": 3}
+	CollisionOverride.merge(document, reserved)
+	suite.Equal(map[string]interface{}{LogCtxMessage: "user message", LogCtxLogLevel: "Info", "count": 3}, document)
+}
+
+func (suite *CollisionPolicyTestSuite) TestJsonFormatters() {
+
+	logContext := newEmptyLogContext().AppendFields(String(LogCtxMessage, "user message"))
+	record := newRecord(Info, "Test Message", logContext)
+	record.Time = time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC)
+
+	formatters := map[string]LogFormatter{
+		"logzio":       &LogzioJsonFormatter{},
+		"datadog":      &DatadogJsonFormatter{},
+		"cloudlogging": &CloudLoggingFormatter{},
+		"emf":          &EmfFormatter{},
+	}
+	for name, formatter := range formatters {
+
+		document := suite.format(formatter, record)
+		suite.Equal("Test Message", document[LogCtxMessage], name)
+		suite.Equal("user message", document[COLLISION_PREFIX+LogCtxMessage], name)
+
+		formatter.(interface{ setCollisionPolicy(CollisionPolicy) }).setCollisionPolicy(CollisionOverride)
+		document = suite.format(formatter, record)
+		suite.Equal("user message", document[LogCtxMessage], name)
+		suite.NotContains(document, COLLISION_PREFIX+LogCtxMessage, name)
+	}
+}
+
+func (suite *CollisionPolicyTestSuite) TestEcsJsonFormatter() {
+
+	logContext := newEmptyLogContext().AppendFields(String("log.level", "user level"), String("message", "user message"))
+	record := newRecord(Info, "Test Message", logContext)
+	formatter := &EcsJsonFormatter{}
+
+	document := suite.format(formatter, record)
+	suite.Equal("Test Message", document["message"])
+	suite.Equal("info", document["log"].(map[string]interface{})["level"])
+	fields := document["fields"].(map[string]interface{})
+	suite.Equal("user message", fields["message"])
+	suite.Equal("user level", fields["log"].(map[string]interface{})["level"])
+
+	formatter.setCollisionPolicy(CollisionReject)
+	document = suite.format(formatter, record)
+	suite.Equal("Test Message", document["message"])
+	suite.NotContains(document, "fields")
+}
+
+func (suite *CollisionPolicyTestSuite) TestMergeNested() {
+
+	reserved := func() map[string]interface{} {
+		return map[string]interface{}{"service.name": "billing", "log.level": "info", "message": "Test Message"}
+	}
+
+	document := map[string]interface{}{"service.name.x": "user value", "log": "user log", "labels.count": "3"}
+	CollisionRename.mergeNested(document, reserved())
+	suite.Equal(map[string]interface{}{
+		"service.name":                      "billing",
+		"log.level":                         "info",
+		"message":                           "Test Message",
+		COLLISION_PREFIX + "service.name.x": "user value",
+		COLLISION_PREFIX + "log":            "user log",
+		"labels.count":                      "3",
+	}, document)
+
+	document = map[string]interface{}{"service.name.x": "user value", "log": "user log", "labels.count": "3"}
+	CollisionReject.mergeNested(document, reserved())
+	suite.Equal(map[string]interface{}{"service.name": "billing", "log.level": "info", "message": "Test Message", "labels.count": "3"}, document)
+
+	document = map[string]interface{}{"service.name.x": "user value", "log": "user log", "labels.count": "3"}
+	CollisionOverride.mergeNested(document, reserved())
+	suite.Equal(map[string]interface{}{"service.name.x": "user value", "log": "user log", "message": "Test Message", "labels.count": "3"}, document)
+}
+
+func (suite *CollisionPolicyTestSuite) TestEcsJsonFormatterWithNestedCollision() {
+
+	logContext := newEmptyLogContext().AppendFields(String("service.name.x", "user value"), String("log", "user log"))
+	record := newRecord(Info, "Test Message", logContext)
+	record.Namespace = "billing"
+	formatter := &EcsJsonFormatter{}
+
+	document := suite.format(formatter, record)
+	suite.Equal("billing", document["service"].(map[string]interface{})["name"])
+	suite.Equal("info", document["log"].(map[string]interface{})["level"])
+	fields := document["fields"].(map[string]interface{})
+	suite.Equal("user log", fields["log"])
+	suite.Equal("user value", fields["service"].(map[string]interface{})["name"].(map[string]interface{})["x"])
+
+	formatter.setCollisionPolicy(CollisionOverride)
+	document = suite.format(formatter, record)
+	suite.Equal("user log", document["log"])
+	suite.Equal("user value", document["service"].(map[string]interface{})["name"].(map[string]interface{})["x"])
+}
+
+func (suite *CollisionPolicyTestSuite) TestResolveParents() {
+
+	document := map[string]interface{}{"user": "john", "user.id": 42, "user.name": "John", "username": "jd"}
+	CollisionRename.resolveParents(document)
+	suite.Equal(map[string]interface{}{COLLISION_PREFIX + "user": "john", "user.id": 42, "user.name": "John", "username": "jd"}, document)
+
+	for _, policy := range []CollisionPolicy{CollisionReject, CollisionOverride} {
+		document = map[string]interface{}{"user": "john", "user.id": 42, "username": "jd"}
+		policy.resolveParents(document)
+		suite.Equal(map[string]interface{}{"user.id": 42, "username": "jd"}, document)
+	}
+}
+
+func (suite *CollisionPolicyTestSuite) TestEcsJsonFormatterWithParentKeys() {
+
+	logContext := newEmptyLogContext().AppendFields(String("user", "john"), Int("user.id", 42))
+	record := newRecord(Info, "Test Message", logContext)
+	formatter := &EcsJsonFormatter{}
+
+	document := suite.format(formatter, record)
+	suite.Equal(float64(42), document["user"].(map[string]interface{})["id"])
+	suite.Equal("john", document["fields"].(map[string]interface{})["user"])
+
+	formatter.setCollisionPolicy(CollisionReject)
+	document = suite.format(formatter, record)
+	suite.Equal(map[string]interface{}{"id": float64(42)}, document["user"])
+	suite.NotContains(document, "fields")
+}
+
+func (suite *CollisionPolicyTestSuite) format(formatter LogFormatter, record Record) map[string]interface{} {
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	document := make(map[string]interface{})
+	suite.Nil(json.Unmarshal(logMessage, &document))
+	return document
+}
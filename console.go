@@ -0,0 +1,105 @@
+package log
+
+import (
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	config "github.com/tommzn/go-config"
+)
+
+// CONSOLE_MESSAGE_WIDTH is the default width messages are padded to by ConsoleFormatter.
+// Can be set by config: log.formatter.messagewidth
+const CONSOLE_MESSAGE_WIDTH = 40
+
+// CONSOLE_TIMESTAMP_FORMAT is the timestamp format used by ConsoleFormatter.
+const CONSOLE_TIMESTAMP_FORMAT = "15:04:05.000"
+
+// ENV_NO_COLOR is the environment variable which disables colors if it's set, see https://no-color.org.
+const ENV_NO_COLOR = "NO_COLOR"
+
+// ANSI escape sequences used by ConsoleFormatter.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiDim     = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiBlue    = "\x1b[34m"
+	ansiMagenta = "\x1b[35m"
+)
+
+// consoleLevelColors maps log levels to colors of their badges.
+var consoleLevelColors = map[LogLevel]string{
+	Status: ansiMagenta,
+	Error:  ansiRed,
+	Info:   ansiGreen,
+	Debug:  ansiBlue,
+}
+
+// newConsoleFormatter returns a new ConsoleFormatter. Colors are enabled if stdout is a terminal,
+// this can be changed by config: log.formatter.colors. If NO_COLOR is set, colors are always disabled.
+func newConsoleFormatter(conf config.Config) LogFormatter {
+
+	colors := isTerminal(os.Stdout)
+	messageWidth := CONSOLE_MESSAGE_WIDTH
+	if conf != nil {
+		colors = *conf.GetAsBool("log.formatter.colors", config.AsBoolPtr(colors))
+		messageWidth = *conf.GetAsInt("log.formatter.messagewidth", config.AsIntPtr(messageWidth))
+	}
+	if noColor, ok := os.LookupEnv(ENV_NO_COLOR); ok && noColor != "" {
+		colors = false
+	}
+	return &ConsoleFormatter{colors: colors, messageWidth: messageWidth}
+}
+
+// setTimestampFormat defines how the time of a record is rendered.
+func (formatter *ConsoleFormatter) setTimestampFormat(timestampFormat TimestampFormat) {
+	formatter.timestamp = timestampFormat
+}
+
+// Format creates a line with time, level badge, message and all context values and fields as
+// key=value pairs, sorted by key. Messages are padded, so context values are aligned.
+func (formatter *ConsoleFormatter) Format(record Record) ([]byte, error) {
+
+	builder := &strings.Builder{}
+	if !record.Time.IsZero() {
+		builder.WriteString(formatter.colorize(formatter.timestamp.format(record.Time, CONSOLE_TIMESTAMP_FORMAT), ansiDim))
+		builder.WriteString(" ")
+	}
+	builder.WriteString(formatter.colorize(padRight(strings.ToUpper(record.Level.String()), 6), consoleLevelColors[record.Level]))
+	builder.WriteString(" ")
+	builder.WriteString(record.Message)
+
+	pairs := &strings.Builder{}
+	for _, key := range sortedContextKeys(record.Context) {
+		value, _ := record.Context.lookup(key)
+		writeLogfmtPair(pairs, key, value)
+	}
+	if pairs.Len() > 0 {
+		builder.WriteString(strings.Repeat(" ", max(formatter.messageWidth-utf8.RuneCountInString(record.Message), 0)+1))
+		builder.WriteString(formatter.colorize(pairs.String(), ansiDim))
+	}
+	return []byte(builder.String()), nil
+}
+
+// colorize wraps passed value in given ANSI color if colors are enabled.
+func (formatter *ConsoleFormatter) colorize(value, color string) string {
+	if !formatter.colors || color == "" {
+		return value
+	}
+	return color + value + ansiReset
+}
+
+// padRight appends spaces to passed value up to given length.
+func padRight(value string, length int) string {
+	if valueLength := utf8.RuneCountInString(value); valueLength < length {
+		return value + strings.Repeat(" ", length-valueLength)
+	}
+	return value
+}
+
+// isTerminal returns true if passed file is a character device, e.g. a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
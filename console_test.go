@@ -0,0 +1,83 @@
+package log
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ConsoleFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestConsoleFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(ConsoleFormatterTestSuite))
+}
+
+func (suite *ConsoleFormatterTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/console.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	formatter, ok := logger.(*LogHandler).formatter.(*ConsoleFormatter)
+	suite.True(ok)
+	suite.True(formatter.colors)
+	suite.Equal(20, formatter.messageWidth)
+
+	os.Setenv(ENV_NO_COLOR, "1")
+	suite.False(newConsoleFormatter(conf).(*ConsoleFormatter).colors)
+	os.Unsetenv(ENV_NO_COLOR)
+
+	// Stdout is not a terminal during tests
+	suite.False(newConsoleFormatter(nil).(*ConsoleFormatter).colors)
+}
+
+func (suite *ConsoleFormatterTestSuite) TestCreateFromEnv() {
+
+	os.Setenv(ENV_LOGFORMATTER, "console")
+	logger := NewLoggerFromConfig(loadConfigFromFile("config/logfmt.yml"), nil)
+	suite.IsType(&ConsoleFormatter{}, logger.(*LogHandler).formatter)
+	os.Unsetenv(ENV_LOGFORMATTER)
+}
+
+func (suite *ConsoleFormatterTestSuite) TestFormat() {
+
+	formatter := &ConsoleFormatter{messageWidth: 20}
+	logContext := newLogContext(map[string]string{LogCtxNamespace: "billing"}).AppendFields(Int("count", 3), String("path", "/a b"))
+
+	logMessage, err := formatter.Format(suite.recordForTest(Error, "Test Message", logContext))
+	suite.Nil(err)
+	suite.Equal(`12:08:47.123 ERROR  Test Message         count=3 namespace=billing path="/a b"`, string(logMessage))
+
+	logMessage, err = formatter.Format(suite.recordForTest(Status, "A very long test message", logContext))
+	suite.Nil(err)
+	suite.Equal(`12:08:47.123 STATUS A very long test message count=3 namespace=billing path="/a b"`, string(logMessage))
+
+	logMessage, err = formatter.Format(suite.recordForTest(Info, "Test Message", newEmptyLogContext()))
+	suite.Nil(err)
+	suite.Equal(`12:08:47.123 INFO   Test Message`, string(logMessage))
+}
+
+func (suite *ConsoleFormatterTestSuite) TestFormatWithColors() {
+
+	formatter := &ConsoleFormatter{colors: true, messageWidth: 0}
+	logContext := newEmptyLogContext().AppendFields(Int("count", 3))
+
+	logMessage, err := formatter.Format(suite.recordForTest(Debug, "Test Message", logContext))
+	suite.Nil(err)
+	suite.Equal("\x1b[2m12:08:47.123\x1b[0m \x1b[34mDEBUG \x1b[0m Test Message \x1b[2mcount=3\x1b[0m", string(logMessage))
+
+	logMessage, err = formatter.Format(suite.recordForTest(None, "Test Message", newEmptyLogContext()))
+	suite.Nil(err)
+	suite.Equal("\x1b[2m12:08:47.123\x1b[0m NONE   Test Message", string(logMessage))
+}
+
+func (suite *ConsoleFormatterTestSuite) recordForTest(logLevel LogLevel, message string, logContext LogContext) Record {
+	return Record{
+		Time:    time.Date(2021, 5, 30, 12, 8, 47, 123456789, time.Local),
+		Level:   logLevel,
+		Message: message,
+		Context: logContext,
+	}
+}
@@ -32,11 +32,19 @@ func LogContextWithValues(ctx context.Context, values map[string]string) context
 
 	logContext := newLogContext(values)
 	if currentLogContext, ok := ctx.Value(logContextKey).(LogContext); ok {
-		logContext = logContext.AppendValues(currentLogContext.values)
+		logContext = logContext.withValues(currentLogContext.values)
+		logContext.fields = currentLogContext.fields
 	}
 	return context.WithValue(ctx, logContextKey, logContext)
 }
 
+// LogContextWithFields adds passed fields to the log context of given context.
+func LogContextWithFields(ctx context.Context, fields ...Field) context.Context {
+
+	logContext := getLogContext(ctx)
+	return context.WithValue(ctx, logContextKey, logContext.AppendFields(fields...))
+}
+
 // getLogContext will extract log context from passed context.
 // If there's no log context it will return an empty one.
 func getLogContext(ctx context.Context) LogContext {
@@ -47,22 +55,91 @@ func getLogContext(ctx context.Context) LogContext {
 	return newLogContext(make(map[string]string))
 }
 
-// AppendValues reads values from current log context, append passed values and returns a new log context.
+// AppendValues returns a new log context with values of current log context and passed values.
+// Current log context is not changed.
 func (logContext LogContext) AppendValues(values map[string]string) LogContext {
+	return logContext.withValues(values)
+}
 
-	currentValues := logContext.values
+// withValues returns a new log context with values of current log context and passed values.
+// Current log context is not changed, values are copied to the new log context.
+func (logContext LogContext) withValues(values map[string]string) LogContext {
+
+	newValues := make(map[string]string, len(logContext.values)+len(values))
+	for key, value := range logContext.values {
+		newValues[key] = value
+	}
 	for key, value := range values {
-		currentValues[key] = value
+		newValues[key] = value
+	}
+	return LogContext{values: newValues, fields: logContext.fields}
+}
+
+// AppendFields returns a new log context with passed fields added to the fields of current log context.
+// An existing field is replaced by a passed field with the same key.
+func (logContext LogContext) AppendFields(fields ...Field) LogContext {
+
+	newFields := make([]Field, 0, len(logContext.fields)+len(fields))
+	newFields = append(newFields, logContext.fields...)
+	for _, field := range fields {
+		if idx := indexOfField(newFields, field.Key); idx >= 0 {
+			newFields[idx] = field
+		} else {
+			newFields = append(newFields, field)
+		}
+	}
+	return LogContext{values: logContext.values, fields: newFields}
+}
+
+// indexOfField returns the index of a field with given key or -1 if there's no such field.
+func indexOfField(fields []Field, key string) int {
+	for idx, field := range fields {
+		if field.Key == key {
+			return idx
+		}
 	}
-	return newLogContext(currentValues)
+	return -1
 }
 
-// String creates a string representation of internal values map.
+// Values returns a copy of all context values.
+func (logContext LogContext) Values() map[string]string {
+
+	values := make(map[string]string, len(logContext.values))
+	for key, value := range logContext.values {
+		values[key] = value
+	}
+	return values
+}
+
+// Fields returns a copy of all fields of current log context.
+func (logContext LogContext) Fields() []Field {
+
+	fields := make([]Field, len(logContext.fields))
+	copy(fields, logContext.fields)
+	return fields
+}
+
+// lookup returns the value for passed key as string. Fields take precedence over context values.
+func (logContext LogContext) lookup(key string) (string, bool) {
+
+	if idx := indexOfField(logContext.fields, key); idx >= 0 {
+		return logContext.fields[idx].text(), true
+	}
+	value, ok := logContext.values[key]
+	return value, ok
+}
+
+// String creates a string representation of internal values map and all fields.
 func (logContext LogContext) String() string {
 
 	values := []string{}
 	for key, val := range logContext.values {
-		values = append(values, fmt.Sprintf("%s:%s", key, val))
+		if indexOfField(logContext.fields, key) < 0 {
+			values = append(values, fmt.Sprintf("%s:%s", key, val))
+		}
+	}
+	for _, field := range logContext.fields {
+		values = append(values, field.String())
 	}
 	sort.Strings(values)
 	return strings.Join(values, ",")
@@ -56,8 +56,11 @@ func (suite *ContextTestSuite) TestAppendValues() {
 
 	logContext := newLogContext(suite.contextValuesForTest())
 	suite.Len(logContext.values, 2)
-	logContext.AppendValues(additionalContextValues)
-	suite.Len(logContext.values, 3)
+	logContext2 := logContext.AppendValues(additionalContextValues)
+	suite.Len(logContext.values, 2)
+	suite.Len(logContext2.values, 3)
+	suite.NotContains(logContext.values, "Key3")
+	suite.Equal("Value3", logContext2.values["Key3"])
 }
 
 func (suite *ContextTestSuite) TestDefaultContextForNodes() {
@@ -0,0 +1,147 @@
+package log
+
+import (
+	"os"
+	"strings"
+
+	config "github.com/tommzn/go-config"
+)
+
+// Environment variables used by Datadog for unified service tagging.
+const (
+	ENV_DD_SERVICE = "DD_SERVICE"
+	ENV_DD_ENV     = "DD_ENV"
+	ENV_DD_VERSION = "DD_VERSION"
+)
+
+// DATADOG_TIMESTAMP_FORMAT is the format used for timestamp, UTC with millisecond precision.
+const DATADOG_TIMESTAMP_FORMAT = "2006-01-02T15:04:05.000Z07:00"
+
+// newDatadogJsonFormatter returns a new DatadogJsonFormatter. Service, env and version are read from
+// environment variables DD_SERVICE, DD_ENV and DD_VERSION and can be set by config:
+// log.formatter.service, log.formatter.env and log.formatter.version
+// Additional tags can be set by config as comma separated list: log.formatter.tags
+func newDatadogJsonFormatter(conf config.Config) LogFormatter {
+
+	formatter := &DatadogJsonFormatter{
+		service: os.Getenv(ENV_DD_SERVICE),
+		env:     os.Getenv(ENV_DD_ENV),
+		version: os.Getenv(ENV_DD_VERSION),
+	}
+	if conf != nil {
+		formatter.service = *conf.Get("log.formatter.service", config.AsStringPtr(formatter.service))
+		formatter.env = *conf.Get("log.formatter.env", config.AsStringPtr(formatter.env))
+		formatter.version = *conf.Get("log.formatter.version", config.AsStringPtr(formatter.version))
+		if tags := conf.Get("log.formatter.tags", nil); tags != nil {
+			for _, tag := range strings.Split(*tags, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					formatter.tags = append(formatter.tags, tag)
+				}
+			}
+		}
+	}
+	return formatter
+}
+
+// DatadogStatus returns the Datadog log status for passed log level.
+func DatadogStatus(logLevel LogLevel) string {
+
+	switch logLevel {
+	case Status:
+		return "notice"
+	case Error:
+		return "error"
+	case Info:
+		return "info"
+	case Debug:
+		return "debug"
+	default:
+		return "info"
+	}
+}
+
+// setTimestampFormat defines how the time of a record is rendered.
+func (formatter *DatadogJsonFormatter) setTimestampFormat(timestampFormat TimestampFormat) {
+	formatter.timestamp = timestampFormat
+}
+
+// setCollisionPolicy defines how context values with reserved keys are handled.
+func (formatter *DatadogJsonFormatter) setCollisionPolicy(policy CollisionPolicy) {
+	formatter.collisions = policy
+}
+
+// Format creates a JSON document for passed record. Context values and fields are added as attributes,
+// hostname is used as host and trace and span id are added as dd.trace_id and dd.span_id,
+// which are used by Datadog to correlate logs with APM traces. Context values with a key used
+// by Datadog, e.g. status or service, are handled according to the collision policy.
+func (formatter *DatadogJsonFormatter) Format(record Record) ([]byte, error) {
+
+	document := make(map[string]interface{})
+	for key, value := range record.Context.values {
+		document[key] = value
+	}
+	for _, field := range record.Context.fields {
+		document[field.Key] = field.jsonValue()
+	}
+	delete(document, LogCtxHostname)
+	delete(document, LogCtxTraceId)
+	delete(document, LogCtxSpanId)
+
+	reserved := map[string]interface{}{
+		"status":  DatadogStatus(record.Level),
+		"message": record.Message,
+	}
+	if !record.Time.IsZero() {
+		reserved["timestamp"] = formatter.timestamp.value(record.Time.UTC(), DATADOG_TIMESTAMP_FORMAT)
+	}
+	if service := formatter.serviceFor(record); service != "" {
+		reserved["service"] = service
+	}
+	if formatter.env != "" {
+		reserved["env"] = formatter.env
+	}
+	if formatter.version != "" {
+		reserved["version"] = formatter.version
+	}
+	if hostname, ok := record.Context.lookup(LogCtxHostname); ok && hostname != "" {
+		reserved["host"] = hostname
+	}
+	if correlation := datadogCorrelation(record.Context); len(correlation) > 0 {
+		reserved["dd"] = correlation
+	}
+	if len(formatter.tags) > 0 {
+		reserved["ddtags"] = strings.Join(formatter.tags, ",")
+	}
+	if record.Namespace != "" {
+		reserved["logger"] = map[string]string{"name": record.Namespace}
+	}
+	if record.Error != nil {
+		// Datadog shows error.message as error of a log, so the error field is sent there only.
+		delete(document, LogCtxError)
+		reserved["error"] = map[string]string{"message": record.Error.Error()}
+	}
+	formatter.collisions.merge(document, reserved)
+	return marshalWithFallback(document), nil
+}
+
+// serviceFor returns the configured service or namespace of passed record.
+func (formatter *DatadogJsonFormatter) serviceFor(record Record) string {
+	if formatter.service != "" {
+		return formatter.service
+	}
+	return record.Namespace
+}
+
+// datadogCorrelation returns trace and span id from passed log context,
+// using the names of Datadog tracing libraries.
+func datadogCorrelation(logContext LogContext) map[string]string {
+
+	correlation := make(map[string]string)
+	if traceId, ok := logContext.lookup(LogCtxTraceId); ok && traceId != "" {
+		correlation["trace_id"] = traceId
+	}
+	if spanId, ok := logContext.lookup(LogCtxSpanId); ok && spanId != "" {
+		correlation["span_id"] = spanId
+	}
+	return correlation
+}
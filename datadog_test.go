@@ -0,0 +1,106 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DatadogJsonFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestDatadogJsonFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(DatadogJsonFormatterTestSuite))
+}
+
+func (suite *DatadogJsonFormatterTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/datadog.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&DatadogJsonFormatter{}, logger.(*LogHandler).formatter)
+	formatter := logger.(*LogHandler).formatter.(*DatadogJsonFormatter)
+	suite.Equal("billing-service", formatter.service)
+	suite.Equal("prod", formatter.env)
+	suite.Equal("1.2.3", formatter.version)
+	suite.Equal([]string{"team:payments", "region:eu-west-1"}, formatter.tags)
+}
+
+func (suite *DatadogJsonFormatterTestSuite) TestCreateFromEnvironment() {
+
+	os.Setenv(ENV_DD_SERVICE, "env-service")
+	os.Setenv(ENV_DD_ENV, "staging")
+	os.Setenv(ENV_DD_VERSION, "0.0.1")
+	defer os.Unsetenv(ENV_DD_SERVICE)
+	defer os.Unsetenv(ENV_DD_ENV)
+	defer os.Unsetenv(ENV_DD_VERSION)
+
+	formatter := newDatadogJsonFormatter(nil).(*DatadogJsonFormatter)
+	suite.Equal("env-service", formatter.service)
+	suite.Equal("staging", formatter.env)
+	suite.Equal("0.0.1", formatter.version)
+
+	formatter = newDatadogJsonFormatter(loadConfigFromFile("config/datadog.yml")).(*DatadogJsonFormatter)
+	suite.Equal("billing-service", formatter.service)
+}
+
+func (suite *DatadogJsonFormatterTestSuite) TestStatus() {
+
+	suite.Equal("notice", DatadogStatus(Status))
+	suite.Equal("error", DatadogStatus(Error))
+	suite.Equal("info", DatadogStatus(Info))
+	suite.Equal("debug", DatadogStatus(Debug))
+}
+
+func (suite *DatadogJsonFormatterTestSuite) TestFormat() {
+
+	formatter := &DatadogJsonFormatter{service: "billing-service", env: "prod", version: "1.2.3", tags: []string{"team:payments"}}
+	logContext := newLogContext(map[string]string{
+		LogCtxHostname:  "host01",
+		LogCtxNamespace: "billing",
+		LogCtxTraceId:   "1234567890",
+	}).AppendFields(Int("count", 3), String(LogCtxSpanId, "987654321"), Err(errors.New("timeout")))
+	record := newRecord(Error, "Test Message", logContext)
+	record.Time = time.Date(2021, 5, 30, 12, 8, 47, 123456789, time.UTC)
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	expected := `{
+		"timestamp": "2021-05-30T12:08:47.123Z",
+		"status": "error",
+		"message": "Test Message",
+		"service": "billing-service",
+		"env": "prod",
+		"version": "1.2.3",
+		"host": "host01",
+		"namespace": "billing",
+		"count": 3,
+		"dd": {"trace_id": "1234567890", "span_id": "987654321"},
+		"ddtags": "team:payments",
+		"logger": {"name": "billing"},
+		"error": {"message": "timeout"}
+	}`
+	suite.JSONEq(expected, string(logMessage))
+}
+
+func (suite *DatadogJsonFormatterTestSuite) TestFormatWithoutService() {
+
+	formatter := &DatadogJsonFormatter{}
+	record := newRecord(Info, "Test Message", newLogContext(map[string]string{LogCtxNamespace: "billing"}))
+	record.Time = time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC)
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	expected := `{
+		"timestamp": "2021-05-30T12:08:47.000Z",
+		"status": "info",
+		"message": "Test Message",
+		"service": "billing",
+		"namespace": "billing",
+		"logger": {"name": "billing"}
+	}`
+	suite.JSONEq(expected, string(logMessage))
+}
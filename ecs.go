@@ -0,0 +1,139 @@
+package log
+
+import (
+	"sort"
+	"strings"
+
+	config "github.com/tommzn/go-config"
+)
+
+// ECS_VERSION is the version of Elastic Common Schema used by EcsJsonFormatter.
+const ECS_VERSION = "8.11.0"
+
+// ECS_TIMESTAMP_FORMAT is the format used for @timestamp, UTC with millisecond precision.
+const ECS_TIMESTAMP_FORMAT = "2006-01-02T15:04:05.000Z07:00"
+
+// ecsFieldNames maps context keys to ECS field names. Dots in ECS field names
+// will be converted to nested objects.
+var ecsFieldNames = map[string]string{
+	LogCtxHostname:  "host.hostname",
+	LogCtxIp:        "host.ip",
+	LogCtxK8sNode:   "kubernetes.node.name",
+	LogCtxK8sPod:    "kubernetes.pod.name",
+	LogCtxRequestId: "trace.id",
+	LogCtxNamespace: "log.logger",
+	LogCtxDomain:    "labels.domain",
+}
+
+// newEcsJsonFormatter returns a new EcsJsonFormatter.
+// Service name can be set by config: log.formatter.service
+func newEcsJsonFormatter(conf config.Config) LogFormatter {
+
+	formatter := &EcsJsonFormatter{}
+	if conf != nil {
+		if serviceName := conf.Get("log.formatter.service", nil); serviceName != nil {
+			formatter.serviceName = *serviceName
+		}
+	}
+	return formatter
+}
+
+// setTimestampFormat defines how the time of a record is rendered.
+func (formatter *EcsJsonFormatter) setTimestampFormat(timestampFormat TimestampFormat) {
+	formatter.timestamp = timestampFormat
+}
+
+// setCollisionPolicy defines how context values with reserved keys are handled.
+func (formatter *EcsJsonFormatter) setCollisionPolicy(policy CollisionPolicy) {
+	formatter.collisions = policy
+}
+
+// Format creates an ECS JSON document for passed record. Known context values, e.g. hostname or
+// k8s_pod, are mapped to their ECS fields, other context values are added as labels and fields
+// are added with their native types. Dots in keys of fields create nested objects.
+// Fields with the key of an ECS field set by this formatter, e.g. message or log.level, or with a key
+// which is a parent or a child of such a field, e.g. service.name.x, are handled according to the collision policy.
+// The same applies to fields with a key which is a parent of another key, e.g. user and user.id.
+func (formatter *EcsJsonFormatter) Format(record Record) ([]byte, error) {
+
+	values := make(map[string]interface{})
+	for key, value := range record.Context.values {
+		values[ecsFieldName(key)] = value
+	}
+	for _, field := range record.Context.fields {
+		if fieldName, ok := ecsFieldNames[field.Key]; ok {
+			values[fieldName] = field.text()
+		} else {
+			values[field.Key] = field.jsonValue()
+		}
+	}
+
+	reserved := map[string]interface{}{
+		"log.level":   strings.ToLower(record.Level.String()),
+		"message":     record.Message,
+		"ecs.version": ECS_VERSION,
+	}
+	if !record.Time.IsZero() {
+		reserved["@timestamp"] = formatter.timestamp.value(record.Time.UTC(), ECS_TIMESTAMP_FORMAT)
+	}
+	if serviceName := formatter.serviceNameFor(record); serviceName != "" {
+		reserved["service.name"] = serviceName
+	}
+	if record.Error != nil {
+		// ECS defines error.message for the error of a record, so the error field is not added again.
+		delete(values, LogCtxError)
+		reserved["error.message"] = record.Error.Error()
+	}
+	if record.Caller.IsDefined() {
+		reserved["log.origin.file.name"] = record.Caller.File
+		reserved["log.origin.file.line"] = record.Caller.Line
+		reserved["log.origin.function"] = record.Caller.Function
+	}
+	formatter.collisions.mergeNested(values, reserved)
+	formatter.collisions.resolveParents(values)
+
+	document := make(map[string]interface{})
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		setNestedValue(document, key, values[key])
+	}
+	return marshalWithFallback(document), nil
+}
+
+// serviceNameFor returns the configured service name or namespace of passed record.
+func (formatter *EcsJsonFormatter) serviceNameFor(record Record) string {
+	if formatter.serviceName != "" {
+		return formatter.serviceName
+	}
+	return record.Namespace
+}
+
+// ecsFieldName returns the ECS field name for a context key. Unknown keys are added as labels,
+// with dots replaced by underscores because labels can not be nested.
+func ecsFieldName(key string) string {
+	if fieldName, ok := ecsFieldNames[key]; ok {
+		return fieldName
+	}
+	return "labels." + strings.ReplaceAll(key, ".", "_")
+}
+
+// setNestedValue sets passed value in given document. Dots in passed key are used to
+// create nested objects. An existing value is replaced, even if it's a nested object,
+// so collisions of parent keys have to be resolved before.
+func setNestedValue(document map[string]interface{}, key string, value interface{}) {
+
+	path := strings.Split(key, ".")
+	for _, name := range path[:len(path)-1] {
+		child, ok := document[name].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			document[name] = child
+		}
+		document = child
+	}
+	document[path[len(path)-1]] = value
+}
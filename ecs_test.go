@@ -0,0 +1,81 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EcsJsonFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestEcsJsonFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(EcsJsonFormatterTestSuite))
+}
+
+func (suite *EcsJsonFormatterTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/ecs.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&EcsJsonFormatter{}, logger.(*LogHandler).formatter)
+	suite.Equal("billing-service", logger.(*LogHandler).formatter.(*EcsJsonFormatter).serviceName)
+}
+
+func (suite *EcsJsonFormatterTestSuite) TestFormat() {
+
+	logContext := newLogContext(map[string]string{
+		LogCtxHostname:  "host-1",
+		LogCtxK8sNode:   "node-1",
+		LogCtxK8sPod:    "pod-1",
+		LogCtxRequestId: "req-1",
+		LogCtxNamespace: "billing",
+		"tenant.name":   "acme",
+	}).AppendFields(Int("http.response.status_code", 500), Err(errors.New("request failed")))
+	record := Record{
+		Time:      time.Date(2021, 5, 30, 12, 8, 47, 123456789, time.UTC),
+		Level:     Error,
+		Message:   "Test Message",
+		Context:   logContext,
+		Namespace: "billing",
+		Error:     errors.New("request failed"),
+		Caller:    Caller{Function: "main.run", File: "main.go", Line: 12},
+	}
+
+	logMessage, err := newEcsJsonFormatter(nil).Format(record)
+	suite.Nil(err)
+
+	expected := `{
+		"@timestamp": "2021-05-30T12:08:47.123Z",
+		"ecs": {"version": "8.11.0"},
+		"error": {"message": "request failed"},
+		"host": {"hostname": "host-1"},
+		"http": {"response": {"status_code": 500}},
+		"kubernetes": {"node": {"name": "node-1"}, "pod": {"name": "pod-1"}},
+		"labels": {"tenant_name": "acme"},
+		"log": {
+			"level": "error",
+			"logger": "billing",
+			"origin": {"file": {"name": "main.go", "line": 12}, "function": "main.run"}
+		},
+		"message": "Test Message",
+		"service": {"name": "billing"},
+		"trace": {"id": "req-1"}
+	}`
+	suite.JSONEq(expected, string(logMessage))
+}
+
+func (suite *EcsJsonFormatterTestSuite) TestFormatWithServiceName() {
+
+	formatter := &EcsJsonFormatter{serviceName: "billing-service"}
+	logMessage, err := formatter.Format(Record{Level: Info, Message: "Test Message", Namespace: "billing"})
+	suite.Nil(err)
+
+	document := make(map[string]interface{})
+	suite.Nil(json.Unmarshal(logMessage, &document))
+	suite.Equal(map[string]interface{}{"name": "billing-service"}, document["service"])
+	suite.Equal(map[string]interface{}{"level": "info"}, document["log"])
+}
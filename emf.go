@@ -0,0 +1,162 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	config "github.com/tommzn/go-config"
+)
+
+// EMF_NAMESPACE is the default CloudWatch namespace for metrics.
+// Can be set by config: log.formatter.namespace
+const EMF_NAMESPACE = "aws-embedded-metrics"
+
+// EMF_MAX_METRICS is the maximum number of metrics CloudWatch accepts in a single EMF document.
+// Records with more metrics are split into several documents, separated by a newline.
+const EMF_MAX_METRICS = 100
+
+// newEmfFormatter returns a new EmfFormatter.
+// Namespace can be set by config: log.formatter.namespace
+// Context keys used as dimensions can be set by config as comma separated list: log.formatter.dimensions
+func newEmfFormatter(conf config.Config) LogFormatter {
+
+	formatter := &EmfFormatter{namespace: EMF_NAMESPACE}
+	if conf != nil {
+		formatter.namespace = *conf.Get("log.formatter.namespace", config.AsStringPtr(EMF_NAMESPACE))
+		if dimensions := conf.Get("log.formatter.dimensions", nil); dimensions != nil {
+			for _, dimension := range strings.Split(*dimensions, ",") {
+				if dimension = strings.TrimSpace(dimension); dimension != "" {
+					formatter.dimensions = append(formatter.dimensions, dimension)
+				}
+			}
+		}
+	}
+	return formatter
+}
+
+// setTimestampFormat defines how the time of a record is rendered.
+func (formatter *EmfFormatter) setTimestampFormat(timestampFormat TimestampFormat) {
+	formatter.timestamp = timestampFormat
+}
+
+// setCollisionPolicy defines how context values with reserved keys are handled.
+func (formatter *EmfFormatter) setCollisionPolicy(policy CollisionPolicy) {
+	formatter.collisions = policy
+}
+
+// Format creates a JSON document with all context values and fields of passed record as properties.
+// If a record contains metrics, _aws metadata is added, so CloudWatch extracts these metrics.
+// Dimensions passed with metrics and configured context keys are used as dimensions. Their values
+// are converted to strings, because CloudWatch accepts string values for dimensions, only.
+// Context values with key loglevel, message, timestamp or _aws are handled according to the collision policy.
+// Because _aws metadata is required for metrics, a context value with key _aws is renamed for CollisionOverride.
+func (formatter *EmfFormatter) Format(record Record) ([]byte, error) {
+
+	properties := make(map[string]interface{})
+	for key, value := range record.Context.values {
+		properties[key] = value
+	}
+	for _, field := range record.Context.fields {
+		properties[field.Key] = field.jsonValue()
+	}
+	reserved := map[string]interface{}{
+		LogCtxLogLevel: record.Level.String(),
+		LogCtxMessage:  record.Message,
+	}
+
+	if len(record.Metrics) == 0 {
+		if !record.Time.IsZero() {
+			reserved[LogCtxTimestamp] = record.Time.UnixMilli()
+			if formatter.timestamp.isDefined() {
+				reserved[LogCtxTimestamp] = formatter.timestamp.value(record.Time.UTC(), time.RFC3339Nano)
+			}
+		}
+		formatter.collisions.merge(properties, reserved)
+		return marshalWithFallback(properties), nil
+	}
+	formatter.collisions.merge(properties, reserved)
+
+	dimensions := formatter.dimensionsFor(record)
+	for _, dimension := range dimensions {
+		properties[dimension], _ = record.Context.lookup(dimension)
+	}
+	documents := [][]byte{}
+	for start := 0; start < len(record.Metrics); start += EMF_MAX_METRICS {
+		end := start + EMF_MAX_METRICS
+		if end > len(record.Metrics) {
+			end = len(record.Metrics)
+		}
+		documents = append(documents, formatter.document(record, properties, dimensions, record.Metrics[start:end]))
+	}
+	return bytes.Join(documents, []byte("\n")), nil
+}
+
+// document creates an EMF document for passed metrics. Values of all metrics are added
+// as properties and their names and units as metric definitions.
+func (formatter *EmfFormatter) document(record Record, properties map[string]interface{}, dimensions []string, metrics []Metric) []byte {
+
+	document := make(map[string]interface{}, len(properties)+len(metrics)+1)
+	for key, value := range properties {
+		document[key] = value
+	}
+
+	definitions := make([]map[string]string, 0, len(metrics))
+	for _, metric := range metrics {
+		unit := metric.Unit
+		if unit == "" {
+			unit = UnitNone
+		}
+		definitions = append(definitions, map[string]string{"Name": metric.Name, "Unit": string(unit)})
+		document[metric.Name] = metric.Value
+	}
+	// EMF requires a timestamp, so current time is used for records without time.
+	timestamp := record.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	// CloudWatch drops metrics without _aws metadata, so it can't be overridden by a context value.
+	policy := formatter.collisions
+	if policy == CollisionOverride {
+		policy = CollisionRename
+	}
+	policy.merge(document, map[string]interface{}{
+		"_aws": map[string]interface{}{
+			"Timestamp": timestamp.UnixMilli(),
+			"CloudWatchMetrics": []map[string]interface{}{
+				{
+					"Namespace":  formatter.namespace,
+					"Dimensions": [][]string{dimensions},
+					"Metrics":    definitions,
+				},
+			},
+		},
+	})
+	return marshalWithFallback(document)
+}
+
+// dimensionsFor returns keys of dimensions passed with metrics and of configured dimensions
+// which are present in the context of passed record.
+func (formatter *EmfFormatter) dimensionsFor(record Record) []string {
+
+	dimensions := []string{}
+	for _, dimension := range append(append([]string{}, record.Dimensions...), formatter.dimensions...) {
+		if containsString(dimensions, dimension) {
+			continue
+		}
+		if _, ok := record.Context.lookup(dimension); ok {
+			dimensions = append(dimensions, dimension)
+		}
+	}
+	return dimensions
+}
+
+// containsString returns true if passed value is in given list.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
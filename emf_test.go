@@ -0,0 +1,169 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EmfFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestEmfFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(EmfFormatterTestSuite))
+}
+
+func (suite *EmfFormatterTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/emf.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&EmfFormatter{}, logger.(*LogHandler).formatter)
+	formatter := logger.(*LogHandler).formatter.(*EmfFormatter)
+	suite.Equal("billing", formatter.namespace)
+	suite.Equal([]string{"k8s_pod", "domain"}, formatter.dimensions)
+
+	suite.Equal(EMF_NAMESPACE, newEmfFormatter(nil).(*EmfFormatter).namespace)
+}
+
+func (suite *EmfFormatterTestSuite) TestFormatWithoutMetrics() {
+
+	formatter := &EmfFormatter{namespace: EMF_NAMESPACE}
+	record := newRecord(Info, "Test Message", newLogContext(map[string]string{LogCtxDomain: "payments"}))
+	record.Time = time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC)
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	expected := `{"domain": "payments", "loglevel": "Info", "message": "Test Message", "timestamp": 1622376527000}`
+	suite.JSONEq(expected, string(logMessage))
+}
+
+func (suite *EmfFormatterTestSuite) TestFormatWithMetrics() {
+
+	formatter := &EmfFormatter{namespace: "billing", dimensions: []string{LogCtxDomain, LogCtxK8sPod}}
+	logContext := newLogContext(map[string]string{LogCtxDomain: "payments", LogCtxRequestId: "req-1"}).
+		AppendFields(Int("shard", 2))
+	record := newRecord(Status, "latency, invoices", logContext)
+	record.Time = time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC)
+	record.Metrics = []Metric{{Name: "latency", Value: 12.5, Unit: UnitMilliseconds}, {Name: "invoices", Value: 3}}
+	record.Dimensions = []string{"shard"}
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	expected := `{
+		"_aws": {
+			"Timestamp": 1622376527000,
+			"CloudWatchMetrics": [{
+				"Namespace": "billing",
+				"Dimensions": [["shard", "domain"]],
+				"Metrics": [{"Name": "latency", "Unit": "Milliseconds"}, {"Name": "invoices", "Unit": "None"}]
+			}]
+		},
+		"domain": "payments",
+		"requestid": "req-1",
+		"shard": "2",
+		"loglevel": "Status",
+		"message": "latency, invoices",
+		"latency": 12.5,
+		"invoices": 3
+	}`
+	suite.JSONEq(expected, string(logMessage))
+}
+
+func (suite *EmfFormatterTestSuite) TestFormatWithReservedMetadataKey() {
+
+	logContext := newEmptyLogContext().AppendFields(String("_aws", "user value"))
+	record := newRecord(Status, "latency", logContext)
+	record.Metrics = []Metric{{Name: "latency", Value: 12.5, Unit: UnitMilliseconds}}
+
+	for _, policy := range []CollisionPolicy{CollisionRename, CollisionOverride} {
+		formatter := &EmfFormatter{namespace: "billing", collisions: policy}
+		logMessage, err := formatter.Format(record)
+		suite.Nil(err)
+		suite.Len(emfMetricDefinitionsForTest(suite, string(logMessage)), 1)
+		suite.Contains(string(logMessage), `"`+COLLISION_PREFIX+`_aws":"user value"`)
+	}
+
+	formatter := &EmfFormatter{namespace: "billing", collisions: CollisionReject}
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	suite.Len(emfMetricDefinitionsForTest(suite, string(logMessage)), 1)
+	suite.NotContains(string(logMessage), "user value")
+}
+
+func (suite *EmfFormatterTestSuite) TestFormatSplitsMetrics() {
+
+	formatter := &EmfFormatter{namespace: EMF_NAMESPACE}
+	record := newRecord(Status, "metrics", newEmptyLogContext())
+	for i := 0; i < 150; i++ {
+		record.Metrics = append(record.Metrics, Metric{Name: fmt.Sprintf("metric%d", i), Value: float64(i), Unit: UnitCount})
+	}
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	documents := strings.Split(string(logMessage), "\n")
+	suite.Len(documents, 2)
+	suite.Equal(EMF_MAX_METRICS, len(emfMetricDefinitionsForTest(suite, documents[0])))
+	suite.Equal(50, len(emfMetricDefinitionsForTest(suite, documents[1])))
+}
+
+func (suite *EmfFormatterTestSuite) TestLogMetrics() {
+
+	shipper := newTestShipper()
+	logger := NewLogger(Info, &EmfFormatter{namespace: "billing"}, shipper).
+		With(String(LogCtxDomain, "payments"))
+
+	logger.Metric("latency", 12.5, UnitMilliseconds, String("operation", "create"))
+	logger.Metrics([]Metric{{Name: "invoices", Value: 3, Unit: UnitCount}, {Name: "errors", Value: 0, Unit: UnitCount}})
+	NewLogger(None, &EmfFormatter{}, shipper).Metric("latency", 1, UnitMilliseconds)
+
+	messages := shipper.(*testShipper).messages
+	suite.Len(messages, 2)
+	suite.Contains(messages[0], `"Dimensions":[["operation"]]`)
+	suite.Contains(messages[0], `"latency":12.5`)
+	suite.Contains(messages[0], `"domain":"payments"`)
+	suite.Len(emfMetricDefinitionsForTest(suite, messages[1]), 2)
+	suite.Contains(messages[1], `"Dimensions":[[]]`)
+}
+
+func (suite *EmfFormatterTestSuite) TestLogMetricsWithOtherFormatter() {
+
+	shipper := newTestShipper()
+	logger := NewLogger(Info, newLogfmtFormatter(), shipper)
+
+	logger.Metric("latency", 12.5, UnitMilliseconds)
+
+	messages := shipper.(*testShipper).messages
+	suite.Len(messages, 1)
+	suite.Contains(messages[0], `msg=latency`)
+	suite.Contains(messages[0], `latency=12.5`)
+}
+
+func (suite *EmfFormatterTestSuite) TestSlogLoggerMetrics() {
+
+	records := &recordingSlogHandler{}
+	logger := NewLoggerFromSlogHandler(records)
+
+	logger.Metric("latency", 12.5, UnitMilliseconds, String("operation", "create"))
+	logger.Metrics([]Metric{{Name: "invoices", Value: 3, Unit: UnitCount}})
+
+	suite.Len(records.records, 2)
+	suite.Equal(LevelStatus, records.records[0].Level)
+	suite.Equal("latency", records.records[0].Message)
+	suite.Equal(map[string]interface{}{"operation": "create", "latency": 12.5, "units.latency": "Milliseconds"}, attrsOf(records.records[0]))
+	suite.Equal(map[string]interface{}{"invoices": float64(3), "units.invoices": "Count"}, attrsOf(records.records[1]))
+}
+
+// emfMetricDefinitionsForTest returns all metric definitions of passed EMF document.
+func emfMetricDefinitionsForTest(suite *EmfFormatterTestSuite, document string) []interface{} {
+
+	values := make(map[string]interface{})
+	suite.Nil(json.Unmarshal([]byte(document), &values))
+	directives := values["_aws"].(map[string]interface{})["CloudWatchMetrics"].([]interface{})
+	suite.Len(directives, 1)
+	return directives[0].(map[string]interface{})["Metrics"].([]interface{})
+}
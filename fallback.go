@@ -0,0 +1,131 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	config "github.com/tommzn/go-config"
+	secrets "github.com/tommzn/go-secrets"
+)
+
+// FALLBACK_COOL_DOWN is the default time a fallback shipper waits before it retries the primary shipper.
+// Can be set by config: log.fallback.cooldown
+const FALLBACK_COOL_DOWN = 30 * time.Second
+
+// NewFallbackShipper returns a shipper which uses passed primary shipper and passes records to
+// the secondary shipper if the primary shipper fails. After a failure all records are passed
+// to the secondary shipper until given cool down expires and the primary shipper is retried.
+// Errors of asynchronous shippers are observed if they implement AsyncLogShipper.
+// Records are passed to the secondary shipper with their existing payload, use a MultiShipper
+// with a single output as secondary shipper to apply a different formatter.
+func NewFallbackShipper(primary LogShipper, secondary LogShipper, coolDown time.Duration) LogShipper {
+
+	shipper := &FallbackShipper{
+		primary:   primary,
+		secondary: secondary,
+		coolDown:  coolDown,
+	}
+	if asyncShipper, ok := primary.(AsyncLogShipper); ok {
+		asyncShipper.OnError(shipper.primaryFailed)
+	}
+	return shipper
+}
+
+// newFallbackShipperFromConfig wraps passed shipper in a fallback shipper if there's
+// a fallback shipper defined in config, e.g.
+//
+//	log:
+//	  shipper: logzio
+//	  fallback:
+//	    shipper: stdout
+//	    formatter: default
+//	    cooldown: 1m
+//
+// Without a fallback formatter records are passed with the payload created for the primary shipper.
+func newFallbackShipperFromConfig(conf config.Config, primary LogShipper, secretsManager secrets.SecretsManager) LogShipper {
+
+	fallbackConfig := make(map[string]string)
+	if shipperName := conf.Get("log.fallback.shipper", nil); shipperName != nil {
+		fallbackConfig["shipper"] = *shipperName
+	} else {
+		return primary
+	}
+	formatterName := conf.Get("log.fallback.formatter", nil)
+	if formatterName != nil {
+		fallbackConfig["formatter"] = *formatterName
+	}
+
+	formatter, secondary := formatterAndShipperByName(conf, fallbackConfig, secretsManager)
+	if formatterName != nil {
+		secondary = NewMultiShipper(LogOutput{LogLevel: Debug, Formatter: formatter, Shipper: secondary})
+	}
+	coolDown := conf.GetAsDuration("log.fallback.cooldown", config.AsDurationPtr(FALLBACK_COOL_DOWN))
+	return NewFallbackShipper(primary, secondary, *coolDown)
+}
+
+// Ship passes records to the primary shipper. If it fails or cool down after a previous
+// failure is still active, records are passed to the secondary shipper.
+func (shipper *FallbackShipper) Ship(ctx context.Context, records []Record) error {
+
+	if shipper.isCoolingDown() {
+		return shipper.secondary.Ship(ctx, records)
+	}
+
+	if err := shipper.primary.Ship(ctx, records); err != nil {
+		shipper.markFailed()
+		return shipper.secondary.Ship(ctx, records)
+	}
+	shipper.markRecovered()
+	return nil
+}
+
+// Flush calls flush for primary and secondary shipper. Records which can not be flushed
+// by an asynchronous primary shipper are passed to the secondary shipper before it's flushed.
+func (shipper *FallbackShipper) Flush() error {
+
+	var errs []error
+	if err := shipper.primary.Flush(); err != nil {
+		shipper.markFailed()
+		errs = append(errs, err)
+	}
+	if err := shipper.secondary.Flush(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// primaryFailed is registered as error handler at asynchronous primary shippers.
+// It passes all records of a failed shipment to the secondary shipper.
+func (shipper *FallbackShipper) primaryFailed(records []Record, err error) {
+
+	shipper.markFailed()
+	if shipErr := shipper.secondary.Ship(context.Background(), records); shipErr != nil {
+		log.Println(errors.Join(err, shipErr))
+	}
+}
+
+// isCoolingDown returns true if the primary shipper failed and cool down has not expired, yet.
+func (shipper *FallbackShipper) isCoolingDown() bool {
+
+	shipper.mutex.Lock()
+	defer shipper.mutex.Unlock()
+	return !shipper.failedAt.IsZero() && nowFrom(shipper.clock).Before(shipper.failedAt.Add(shipper.coolDown))
+}
+
+// markFailed starts a new cool down.
+func (shipper *FallbackShipper) markFailed() {
+
+	shipper.mutex.Lock()
+	defer shipper.mutex.Unlock()
+	shipper.failedAt = nowFrom(shipper.clock)
+}
+
+// markRecovered resets a previous failure of the primary shipper.
+func (shipper *FallbackShipper) markRecovered() {
+
+	shipper.mutex.Lock()
+	defer shipper.mutex.Unlock()
+	shipper.failedAt = time.Time{}
+}
@@ -0,0 +1,91 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	secrets "github.com/tommzn/go-secrets"
+)
+
+type FallbackShipperTestSuite struct {
+	suite.Suite
+}
+
+func TestFallbackShipperTestSuite(t *testing.T) {
+	suite.Run(t, new(FallbackShipperTestSuite))
+}
+
+func (suite *FallbackShipperTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/fallback.yml")
+	logger := NewLoggerFromConfig(conf, secrets.NewStaticSecretsManager(map[string]string{}))
+
+	fallbackShipper, ok := logger.(*LogHandler).shipper.(*FallbackShipper)
+	suite.True(ok)
+	suite.IsType(&LogzioShipper{}, fallbackShipper.primary)
+	suite.NotNil(fallbackShipper.primary.(*LogzioShipper).errorHandler)
+	suite.IsType(&MultiShipper{}, fallbackShipper.secondary)
+	suite.IsType(&DefaultFormatter{}, fallbackShipper.secondary.(*MultiShipper).outputs[0].Formatter)
+	suite.Equal(time.Minute, fallbackShipper.coolDown)
+
+	conf2 := loadConfigFromFile("config/logzio.yml")
+	logger2 := NewLoggerFromConfig(conf2, nil)
+	suite.IsType(&LogzioShipper{}, logger2.(*LogHandler).shipper)
+}
+
+func (suite *FallbackShipperTestSuite) TestFallbackWithCoolDown() {
+
+	primary := &failingShipperForTest{err: errors.New("shipment failed")}
+	secondary := newTestShipper().(*testShipper)
+	shipper := NewFallbackShipper(primary, secondary, time.Minute).(*FallbackShipper)
+	now := time.Date(2021, 5, 30, 12, 0, 0, 0, time.UTC)
+	shipper.clock = ClockFunc(func() time.Time { return now })
+
+	suite.Nil(shipper.Ship(context.Background(), suite.recordsForTest("Record 1")))
+	suite.Len(primary.records, 1)
+	suite.Equal([]string{"Record 1"}, secondary.messages)
+
+	// Primary shipper is not used during cool down
+	now = now.Add(30 * time.Second)
+	primary.err = nil
+	suite.Nil(shipper.Ship(context.Background(), suite.recordsForTest("Record 2")))
+	suite.Len(primary.records, 1)
+	suite.Equal([]string{"Record 1", "Record 2"}, secondary.messages)
+
+	// Primary shipper is retried after cool down
+	now = now.Add(31 * time.Second)
+	suite.Nil(shipper.Ship(context.Background(), suite.recordsForTest("Record 3")))
+	suite.Nil(shipper.Ship(context.Background(), suite.recordsForTest("Record 4")))
+	suite.Len(primary.records, 3)
+	suite.Equal([]string{"Record 1", "Record 2"}, secondary.messages)
+
+	suite.EqualError(shipper.Flush(), "flush failed")
+	suite.True(shipper.isCoolingDown())
+}
+
+func (suite *FallbackShipperTestSuite) TestFallbackForAsyncShipper() {
+
+	primary := (&LogzioShipperTestSuite{}).shipperForTest()
+	primary.secretsManager = secrets.NewStaticSecretsManager(map[string]string{LOGZIO_TOKEN_KEY: "<LogzioToken>"})
+	primary.httpClient.(*testClient).response = &http.Response{StatusCode: 503}
+	secondary := newTestShipper().(*testShipper)
+	shipper := NewFallbackShipper(primary, secondary, time.Minute)
+
+	for i := 1; i <= primary.batchSize; i++ {
+		suite.Nil(shipper.Ship(context.Background(), suite.recordsForTest("Record")))
+	}
+	suite.Len(secondary.messages, 0)
+
+	suite.Nil(shipper.Flush())
+	suite.Len(primary.httpClient.(*testClient).recordedRequests(), 1)
+	suite.Len(secondary.messages, primary.batchSize)
+	suite.True(shipper.(*FallbackShipper).isCoolingDown())
+}
+
+func (suite *FallbackShipperTestSuite) recordsForTest(payload string) []Record {
+	return []Record{{Level: Info, Message: payload, Payload: []byte(payload)}}
+}
@@ -0,0 +1,190 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// String returns a field with a string value.
+func String(key string, value string) Field {
+	return Field{Key: key, Type: StringType, value: value}
+}
+
+// Int returns a field with an integer value.
+func Int(key string, value int) Field {
+	return Int64(key, int64(value))
+}
+
+// Int64 returns a field with an integer value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: IntType, value: value}
+}
+
+// Float64 returns a field with a floating point value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: FloatType, value: value}
+}
+
+// Bool returns a field with a boolean value.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Type: BoolType, value: value}
+}
+
+// Time returns a field with a timestamp.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Type: TimeType, value: value}
+}
+
+// Duration returns a field with a duration.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: DurationType, value: value}
+}
+
+// Err returns a field with passed error, using LogCtxError as key.
+func Err(err error) Field {
+	return NamedErr(LogCtxError, err)
+}
+
+// NamedErr returns a field with passed error and given key.
+func NamedErr(key string, err error) Field {
+	return Field{Key: key, Type: ErrorType, value: err}
+}
+
+// Any returns a field for an arbitrary value. If the value is of a known type
+// the corresponding typed field is returned, otherwise the value is kept as is,
+// e.g. to render nested objects.
+func Any(key string, value interface{}) Field {
+
+	switch val := value.(type) {
+	case Field:
+		return val
+	case string:
+		return String(key, val)
+	case int:
+		return Int(key, val)
+	case int8:
+		return Int64(key, int64(val))
+	case int16:
+		return Int64(key, int64(val))
+	case int32:
+		return Int64(key, int64(val))
+	case int64:
+		return Int64(key, val)
+	case uint8:
+		return Int64(key, int64(val))
+	case uint16:
+		return Int64(key, int64(val))
+	case uint32:
+		return Int64(key, int64(val))
+	case float32:
+		return Float64(key, float64(val))
+	case float64:
+		return Float64(key, val)
+	case bool:
+		return Bool(key, val)
+	case time.Time:
+		return Time(key, val)
+	case time.Duration:
+		return Duration(key, val)
+	case error:
+		return NamedErr(key, val)
+	default:
+		return Field{Key: key, Type: AnyType, value: value}
+	}
+}
+
+// Value returns the native value of a field.
+func (field Field) Value() interface{} {
+	return field.value
+}
+
+// String returns a key:value representation of a field.
+func (field Field) String() string {
+	return fmt.Sprintf("%s:%s", field.Key, field.text())
+}
+
+// text converts the value of a field into a human readable string.
+func (field Field) text() string {
+
+	switch field.Type {
+	case TimeType:
+		return field.value.(time.Time).Format(time.RFC3339Nano)
+	case ErrorType:
+		if field.value == nil {
+			return "<nil>"
+		}
+		return field.value.(error).Error()
+	default:
+		return fmt.Sprint(field.value)
+	}
+}
+
+// jsonValue returns the value of a field as it should be rendered by JSON formatters.
+// Durations are rendered as nanoseconds, like encoding/json does, and errors by their message.
+func (field Field) jsonValue() interface{} {
+
+	switch field.Type {
+	case DurationType:
+		return int64(field.value.(time.Duration))
+	case ErrorType:
+		if field.value == nil {
+			return nil
+		}
+		return field.value.(error).Error()
+	default:
+		return field.value
+	}
+}
+
+// fieldsFromKeysAndValues converts passed list of alternating keys and values into fields.
+// Items which are already a Field are taken as they are. Non string keys are converted
+// to a string and a key without a value will get a nil value.
+func fieldsFromKeysAndValues(keysAndValues []interface{}) []Field {
+
+	fields := []Field{}
+	for i := 0; i < len(keysAndValues); i++ {
+
+		if field, ok := keysAndValues[i].(Field); ok {
+			fields = append(fields, field)
+			continue
+		}
+
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		if i+1 < len(keysAndValues) {
+			fields = append(fields, Any(key, keysAndValues[i+1]))
+			i++
+		} else {
+			fields = append(fields, Any(key, nil))
+		}
+	}
+	return fields
+}
+
+// marshalWithFallback marshals passed values to JSON. If marshalling fails, e.g. because
+// of an unsupported value in an Any field, values are marshalled one by one and only values
+// which can't be marshalled are converted to strings. Nested objects are handled the same way.
+func marshalWithFallback(values map[string]interface{}) []byte {
+
+	if content, err := json.Marshal(values); err == nil {
+		return content
+	}
+
+	rawValues := make(map[string]json.RawMessage, len(values))
+	for key, value := range values {
+		if object, ok := value.(map[string]interface{}); ok {
+			rawValues[key] = marshalWithFallback(object)
+		} else if content, err := json.Marshal(value); err == nil {
+			rawValues[key] = content
+		} else {
+			// Since we marshal a string here, we'll omit the error
+			rawValues[key], _ = json.Marshal(fmt.Sprint(value))
+		}
+	}
+	// All values are valid JSON here, so we'll omit the error
+	content, _ := json.Marshal(rawValues)
+	return content
+}
@@ -0,0 +1,130 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FieldsTestSuite struct {
+	suite.Suite
+}
+
+func TestFieldsTestSuite(t *testing.T) {
+	suite.Run(t, new(FieldsTestSuite))
+}
+
+func (suite *FieldsTestSuite) TestCreateFields() {
+
+	suite.Equal(StringType, String("key", "val").Type)
+	suite.Equal(IntType, Int("key", 1).Type)
+	suite.Equal(int64(1), Int("key", 1).Value())
+	suite.Equal(FloatType, Float64("key", 1.5).Type)
+	suite.Equal(BoolType, Bool("key", true).Type)
+	suite.Equal(TimeType, Time("key", time.Now()).Type)
+	suite.Equal(DurationType, Duration("key", time.Second).Type)
+	suite.Equal(ErrorType, Err(errors.New("error")).Type)
+	suite.Equal(LogCtxError, Err(errors.New("error")).Key)
+}
+
+func (suite *FieldsTestSuite) TestAnyField() {
+
+	suite.Equal(StringType, Any("key", "val").Type)
+	suite.Equal(IntType, Any("key", int32(3)).Type)
+	suite.Equal(FloatType, Any("key", float32(3.5)).Type)
+	suite.Equal(BoolType, Any("key", false).Type)
+	suite.Equal(DurationType, Any("key", time.Minute).Type)
+	suite.Equal(ErrorType, Any("key", errors.New("error")).Type)
+	suite.Equal(AnyType, Any("key", map[string]int{"a": 1}).Type)
+}
+
+func (suite *FieldsTestSuite) TestFieldAsString() {
+
+	suite.Equal("key:val", String("key", "val").String())
+	suite.Equal("key:12", Int("key", 12).String())
+	suite.Equal("key:1.5s", Duration("key", 1500*time.Millisecond).String())
+	suite.Equal("error:failed", Err(errors.New("failed")).String())
+	suite.Equal("key:2021-05-30T12:08:47Z", Time("key", time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC)).String())
+}
+
+func (suite *FieldsTestSuite) TestFieldsFromKeysAndValues() {
+
+	fields := fieldsFromKeysAndValues([]interface{}{"key1", 1, Bool("key2", true), "key3", "val3", 4})
+	suite.Len(fields, 4)
+	suite.Equal(IntType, fields[0].Type)
+	suite.Equal("key2", fields[1].Key)
+	suite.Equal(StringType, fields[2].Type)
+	suite.Equal("4", fields[3].Key)
+	suite.Nil(fields[3].Value())
+}
+
+func (suite *FieldsTestSuite) TestFieldsInLogContext() {
+
+	logContext := newLogContext(map[string]string{"key1": "val1"})
+	logContext2 := logContext.AppendFields(Int("key2", 2), String("key1", "val2"))
+	suite.Len(logContext.fields, 0)
+	suite.Len(logContext2.fields, 2)
+	suite.Equal("key1:val2,key2:2", logContext2.String())
+
+	logContext3 := logContext2.AppendFields(Int("key2", 3))
+	suite.Len(logContext3.fields, 2)
+	suite.Equal("key1:val2,key2:3", logContext3.String())
+}
+
+func (suite *FieldsTestSuite) TestMarshalWithFallback() {
+
+	content := marshalWithFallback(map[string]interface{}{
+		"count":       3,
+		"unsupported": make(chan int),
+		"object":      map[string]interface{}{"ok": true, "unsupported": func() {}},
+	})
+	values := make(map[string]interface{})
+	suite.Nil(json.Unmarshal(content, &values))
+	suite.Equal(float64(3), values["count"])
+	suite.IsType("", values["unsupported"])
+	object := values["object"].(map[string]interface{})
+	suite.Equal(true, object["ok"])
+	suite.IsType("", object["unsupported"])
+}
+
+func (suite *FieldsTestSuite) TestJsonFormatterWithFields() {
+
+	formatter := newLogzioJsonFormatter()
+	logContext := newEmptyLogContext().AppendFields(
+		Int("count", 3),
+		Float64("ratio", 0.5),
+		Bool("ok", true),
+		Duration("elapsed", time.Millisecond),
+		Err(errors.New("failed")),
+		Any("nested", map[string]int{"a": 1}),
+		Any("unsupported", make(chan int)))
+
+	values := make(map[string]interface{})
+	logMessage, _ := formatter.Format(Record{Level: Info, Context: logContext, Message: "Test Message"})
+	suite.Nil(json.Unmarshal(logMessage, &values))
+	suite.Equal("Test Message", values[LogCtxMessage])
+	suite.Equal(float64(3), values["count"])
+	suite.Equal(map[string]interface{}{"a": float64(1)}, values["nested"])
+	suite.IsType("", values["unsupported"])
+	suite.Len(logContext.values, 0)
+
+	logContext = newEmptyLogContext().AppendFields(
+		Int("count", 3),
+		Float64("ratio", 0.5),
+		Bool("ok", true),
+		Duration("elapsed", time.Millisecond),
+		Err(errors.New("failed")),
+		Any("nested", map[string]int{"a": 1}))
+	values = make(map[string]interface{})
+	logMessage, _ = formatter.Format(Record{Level: Info, Context: logContext, Message: "Test Message"})
+	suite.Nil(json.Unmarshal(logMessage, &values))
+	suite.Equal(float64(3), values["count"])
+	suite.Equal(0.5, values["ratio"])
+	suite.Equal(true, values["ok"])
+	suite.Equal(float64(time.Millisecond), values["elapsed"])
+	suite.Equal("failed", values[LogCtxError])
+	suite.Equal(map[string]interface{}{"a": float64(1)}, values["nested"])
+}
@@ -0,0 +1,367 @@
+package log
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	config "github.com/tommzn/go-config"
+)
+
+// FILE_PATH is the default path of a log file.
+// Can be set by config: log.file.path
+const FILE_PATH = "log/app.log"
+
+// FILE_MAX_SIZE is the default size in megabytes a log file is rotated at. Zero disables rotation by size.
+// Can be set by config: log.file.maxsize
+const FILE_MAX_SIZE = 100
+
+// FILE_MAX_BACKUPS is the default number of rotated log files which are kept.
+// Can be set by config: log.file.maxbackups
+const FILE_MAX_BACKUPS = 5
+
+// FILE_BACKUP_TIMESTAMP_FORMAT is used to add the time of rotation to names of rotated log files.
+const FILE_BACKUP_TIMESTAMP_FORMAT = "20060102T150405.000"
+
+// NewFileShipper returns a shipper which writes payload of all records to a file at passed path. A file
+// is rotated if it exceeds max size in bytes or if given interval expired since it has been opened.
+// Number of kept rotated files is limited by max backups, rotated files are compressed with gzip
+// if compress is true. A zero max size, interval or max backups disables the corresponding limit.
+// The log file is reopened on SIGHUP, e.g. after it has been moved by logrotate. Signal handling runs
+// until the shipper is closed, so a shipper has to be closed explicitly if it's no longer used,
+// either by its Close method or by Close for the logger it's used by.
+func NewFileShipper(path string, maxSize int64, interval time.Duration, maxBackups int, compress bool) LogShipper {
+
+	shipper := &FileShipper{
+		path:       path,
+		maxSize:    maxSize,
+		interval:   interval,
+		maxBackups: maxBackups,
+		compress:   compress,
+		signals:    make(chan os.Signal, 1),
+	}
+	shipper.compressed = sync.NewCond(&shipper.mutex)
+	signal.Notify(shipper.signals, syscall.SIGHUP)
+	go shipper.reopenOnSignal()
+	return shipper
+}
+
+// newFileShipperFromConfig creates a file shipper with settings defined by config, e.g.
+//
+//	log:
+//	  shipper: file
+//	  file:
+//	    path: /var/log/app/app.log
+//	    maxsize: 100
+//	    interval: 24h
+//	    maxbackups: 5
+//	    compress: true
+//
+// Max size is defined in megabytes.
+func newFileShipperFromConfig(conf config.Config) LogShipper {
+
+	path := conf.Get("log.file.path", config.AsStringPtr(FILE_PATH))
+	maxSize := conf.GetAsInt("log.file.maxsize", config.AsIntPtr(FILE_MAX_SIZE))
+	interval := conf.GetAsDuration("log.file.interval", config.AsDurationPtr(0))
+	maxBackups := conf.GetAsInt("log.file.maxbackups", config.AsIntPtr(FILE_MAX_BACKUPS))
+	compress := conf.GetAsBool("log.file.compress", config.AsBoolPtr(false))
+	return NewFileShipper(*path, int64(*maxSize)*1024*1024, *interval, *maxBackups, *compress)
+}
+
+// Ship writes payload of passed records to current log file, each in a separate line.
+// The log file is opened with the first record and rotated before a record is written,
+// if it exceeds max size or rotation interval.
+func (shipper *FileShipper) Ship(ctx context.Context, records []Record) error {
+
+	shipper.mutex.Lock()
+	defer shipper.mutex.Unlock()
+
+	for _, record := range records {
+		line := append(append([]byte{}, record.Payload...), '\n')
+		if err := shipper.prepareFile(int64(len(line))); err != nil {
+			return err
+		}
+		written, err := shipper.file.Write(line)
+		shipper.size += int64(written)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush commits current log file to disk and waits until all rotated files have been compressed.
+func (shipper *FileShipper) Flush() error {
+
+	shipper.mutex.Lock()
+	defer shipper.mutex.Unlock()
+
+	var err error
+	if shipper.file != nil {
+		err = shipper.file.Sync()
+	}
+	for shipper.compressions > 0 {
+		shipper.compressed.Wait()
+	}
+	return err
+}
+
+// Close stops reopening the log file on SIGHUP and closes current log file.
+// It can be called several times, a record shipped after Close opens the log file again.
+func (shipper *FileShipper) Close() error {
+
+	shipper.stopSignals.Do(func() {
+		signal.Stop(shipper.signals)
+		close(shipper.signals)
+	})
+
+	err := shipper.Flush()
+	shipper.mutex.Lock()
+	defer shipper.mutex.Unlock()
+	if shipper.file != nil {
+		err = shipper.file.Close()
+		shipper.file = nil
+	}
+	return err
+}
+
+// reopenOnSignal closes current log file each time a signal is received,
+// so it will be reopened at its path with next record.
+func (shipper *FileShipper) reopenOnSignal() {
+
+	for range shipper.signals {
+		shipper.mutex.Lock()
+		if err := shipper.closeFile(); err != nil {
+			log.Println(err)
+		}
+		shipper.mutex.Unlock()
+	}
+}
+
+// prepareFile opens the log file if necessary and rotates it, if writing passed number of bytes
+// would exceed max size or if rotation interval expired.
+func (shipper *FileShipper) prepareFile(length int64) error {
+
+	if shipper.file == nil {
+		if err := shipper.openFile(); err != nil {
+			return err
+		}
+	}
+	if shipper.needsRotation(length) {
+		return shipper.rotate()
+	}
+	return nil
+}
+
+// needsRotation returns true if writing passed number of bytes to current log file would
+// exceed max size or rotation interval expired. An empty file is never rotated by size.
+func (shipper *FileShipper) needsRotation(length int64) bool {
+
+	if shipper.maxSize > 0 && shipper.size > 0 && shipper.size+length > shipper.maxSize {
+		return true
+	}
+	return shipper.interval > 0 && !nowFrom(shipper.clock).Before(shipper.openedAt.Add(shipper.interval))
+}
+
+// openFile opens the log file for appending and creates it, including its directory, if necessary.
+func (shipper *FileShipper) openFile() error {
+
+	if err := os.MkdirAll(filepath.Dir(shipper.path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(shipper.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	shipper.file = file
+	shipper.size = info.Size()
+	shipper.openedAt = nowFrom(shipper.clock)
+	return nil
+}
+
+// closeFile closes current log file, if it's open.
+func (shipper *FileShipper) closeFile() error {
+
+	if shipper.file == nil {
+		return nil
+	}
+	err := shipper.file.Close()
+	shipper.file = nil
+	return err
+}
+
+// rotate moves current log file to a backup file with the time of rotation in its name
+// and opens a new log file. Backup files are compressed and removed in background.
+func (shipper *FileShipper) rotate() error {
+
+	if err := shipper.closeFile(); err != nil {
+		return err
+	}
+	backupPath := shipper.backupPath()
+	if err := os.Rename(shipper.path, backupPath); err != nil {
+		return err
+	}
+
+	shipper.compressions++
+	go func() {
+		defer shipper.compressionDone()
+		shipper.backupMutex.Lock()
+		defer shipper.backupMutex.Unlock()
+		// A rotated file might have been removed already, if there're more rotations than max backups.
+		if shipper.compress {
+			if err := compressFile(backupPath); err != nil && !os.IsNotExist(err) {
+				log.Println(err)
+			}
+		}
+		if err := shipper.removeBackups(); err != nil {
+			log.Println(err)
+		}
+	}()
+	return shipper.openFile()
+}
+
+// compressionDone decreases the number of pending compressions and wakes up waiting flushes.
+func (shipper *FileShipper) compressionDone() {
+
+	shipper.mutex.Lock()
+	defer shipper.mutex.Unlock()
+	shipper.compressions--
+	shipper.compressed.Broadcast()
+}
+
+// backupPath returns an unused path for a rotated log file, e.g. app.log.20210530T120847.000
+func (shipper *FileShipper) backupPath() string {
+
+	backupPath := shipper.path + "." + nowFrom(shipper.clock).Format(FILE_BACKUP_TIMESTAMP_FORMAT)
+	for i, path := 1, backupPath; ; i++ {
+		if !fileExists(path) && !fileExists(path+".gz") {
+			return path
+		}
+		path = fmt.Sprintf("%s-%d", backupPath, i)
+	}
+}
+
+// backups returns paths of all rotated log files, oldest first. Only files named like backups written
+// by this shipper are returned, e.g. app.log.20210530T120847.000-1 or app.log.20210530T120847.000.gz
+// A rotated file which is compressed at the moment is returned once, with its uncompressed path.
+func (shipper *FileShipper) backups() ([]string, error) {
+
+	entries, err := os.ReadDir(filepath.Dir(shipper.path))
+	if err != nil {
+		return nil, err
+	}
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(filepath.Base(shipper.path)) + `\.(\d{8}T\d{6}\.\d{3})(?:-(\d+))?(\.gz)?$`)
+
+	type backup struct {
+		path      string
+		timestamp string
+		index     int
+	}
+	backups := make(map[string]backup)
+	for _, entry := range entries {
+		match := pattern.FindStringSubmatch(entry.Name())
+		if match == nil || entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(filepath.Dir(shipper.path), entry.Name())
+		key := strings.TrimSuffix(path, ".gz")
+		if _, ok := backups[key]; ok && match[3] != "" {
+			continue
+		}
+		index, _ := strconv.Atoi(match[2])
+		backups[key] = backup{path: path, timestamp: match[1], index: index}
+	}
+
+	sorted := make([]backup, 0, len(backups))
+	for _, backup := range backups {
+		sorted = append(sorted, backup)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].timestamp != sorted[j].timestamp {
+			return sorted[i].timestamp < sorted[j].timestamp
+		}
+		return sorted[i].index < sorted[j].index
+	})
+	paths := make([]string, 0, len(sorted))
+	for _, backup := range sorted {
+		paths = append(paths, backup.path)
+	}
+	return paths, nil
+}
+
+// removeBackups removes the oldest rotated log files if there're more than max backups.
+// A rotated file is removed together with its compressed file.
+func (shipper *FileShipper) removeBackups() error {
+
+	if shipper.maxBackups <= 0 {
+		return nil
+	}
+	paths, err := shipper.backups()
+	if err != nil || len(paths) <= shipper.maxBackups {
+		return err
+	}
+	var errs []error
+	for _, path := range paths[:len(paths)-shipper.maxBackups] {
+		path = strings.TrimSuffix(path, ".gz")
+		for _, file := range []string{path, path + ".gz"} {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// compressFile writes passed file to a gzip file with the same name and extension .gz
+// and removes the uncompressed file afterwards.
+func compressFile(path string) error {
+
+	source, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	target, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(target)
+	if _, err := io.Copy(writer, source); err != nil {
+		writer.Close()
+		target.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		target.Close()
+		return err
+	}
+	if err := target.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// fileExists returns true if there's a file at passed path.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
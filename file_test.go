@@ -0,0 +1,253 @@
+package log
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	config "github.com/tommzn/go-config"
+)
+
+type FileShipperTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestFileShipperTestSuite(t *testing.T) {
+	suite.Run(t, new(FileShipperTestSuite))
+}
+
+func (suite *FileShipperTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+}
+
+func (suite *FileShipperTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/file.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&FileShipper{}, logger.(*LogHandler).shipper)
+	suite.IsType(&LogfmtFormatter{}, logger.(*LogHandler).formatter)
+
+	shipper := logger.(*LogHandler).shipper.(*FileShipper)
+	defer shipper.Close()
+	suite.Equal("/tmp/go-log-test/app.log", shipper.path)
+	suite.Equal(int64(10*1024*1024), shipper.maxSize)
+	suite.Equal(24*time.Hour, shipper.interval)
+	suite.Equal(3, shipper.maxBackups)
+	suite.True(shipper.compress)
+}
+
+func (suite *FileShipperTestSuite) TestCreateFromConfigWithoutFormatter() {
+
+	path := filepath.Join(suite.dir, "app.log")
+	conf, err := config.NewStaticConfigSource("log:\n  loglevel: info\n  shipper: file\n  file:\n    path: " + path + "\n").Load()
+	suite.Nil(err)
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&DefaultFormatter{}, logger.(*LogHandler).formatter)
+	defer logger.(*LogHandler).shipper.(*FileShipper).Close()
+
+	logger.Info("message 1")
+	logger.Flush()
+	suite.Contains(suite.readFile(path), "message 1")
+}
+
+func (suite *FileShipperTestSuite) TestShip() {
+
+	path := filepath.Join(suite.dir, "logs", "app.log")
+	shipper := NewFileShipper(path, 0, 0, 0, false).(*FileShipper)
+	defer shipper.Close()
+
+	suite.Nil(shipper.Ship(context.Background(), recordsForFileTest("message 1", "message 2")))
+	suite.Nil(shipper.Flush())
+	suite.Equal("message 1\nmessage 2\n", suite.readFile(path))
+}
+
+func (suite *FileShipperTestSuite) TestRotateBySize() {
+
+	path := filepath.Join(suite.dir, "app.log")
+	shipper := NewFileShipper(path, 20, 0, 1, false).(*FileShipper)
+	defer shipper.Close()
+	now := time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC)
+	shipper.clock = ClockFunc(func() time.Time { return now })
+
+	for _, message := range []string{"message 1", "message 2", "message 3", "message 4", "message 5"} {
+		suite.Nil(shipper.Ship(context.Background(), recordsForFileTest(message)))
+	}
+	suite.Nil(shipper.Flush())
+
+	suite.Equal("message 5\n", suite.readFile(path))
+	backups, err := shipper.backups()
+	suite.Nil(err)
+	suite.Equal([]string{path + ".20210530T120847.000-1"}, backups)
+	suite.Equal("message 3\nmessage 4\n", suite.readFile(backups[0]))
+}
+
+func (suite *FileShipperTestSuite) TestRotateByInterval() {
+
+	path := filepath.Join(suite.dir, "app.log")
+	shipper := NewFileShipper(path, 0, time.Hour, 0, true).(*FileShipper)
+	defer shipper.Close()
+	now := time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC)
+	shipper.clock = ClockFunc(func() time.Time { return now })
+
+	suite.Nil(shipper.Ship(context.Background(), recordsForFileTest("message 1")))
+	now = now.Add(30 * time.Minute)
+	suite.Nil(shipper.Ship(context.Background(), recordsForFileTest("message 2")))
+	now = now.Add(30 * time.Minute)
+	suite.Nil(shipper.Ship(context.Background(), recordsForFileTest("message 3")))
+	suite.Nil(shipper.Flush())
+
+	suite.Equal("message 3\n", suite.readFile(path))
+	backups, err := shipper.backups()
+	suite.Nil(err)
+	suite.Equal([]string{path + ".20210530T130847.000.gz"}, backups)
+	suite.Equal("message 1\nmessage 2\n", suite.readGzipFile(backups[0]))
+}
+
+func (suite *FileShipperTestSuite) TestShipAndFlushConcurrently() {
+
+	path := filepath.Join(suite.dir, "app.log")
+	shipper := NewFileShipper(path, 50, 0, 3, true).(*FileShipper)
+	defer shipper.Close()
+
+	wg := sync.WaitGroup{}
+	for writer := 0; writer < 4; writer++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				suite.Nil(shipper.Ship(context.Background(), recordsForFileTest(fmt.Sprintf("writer %d, message %d", writer, i))))
+				if i%5 == 0 {
+					suite.Nil(shipper.Flush())
+				}
+			}
+		}(writer)
+	}
+	wg.Wait()
+	suite.Nil(shipper.Flush())
+
+	backups, err := shipper.backups()
+	suite.Nil(err)
+	suite.Len(backups, 3)
+	for _, backup := range backups {
+		suite.True(strings.HasSuffix(backup, ".gz"))
+	}
+}
+
+func (suite *FileShipperTestSuite) TestBackups() {
+
+	path := filepath.Join(suite.dir, "app.log")
+	shipper := NewFileShipper(path, 0, 0, 2, false).(*FileShipper)
+	defer shipper.Close()
+
+	files := []string{"app.log.lock", "app.log.bak", "app.log.1", "app.log.20210530T120847.000.txt", "other.log.20210530T120847.000",
+		"app.log.20210530T120847.000", "app.log.20210530T120847.000.gz", "app.log.20210530T120847.000-10.gz",
+		"app.log.20210530T120847.000-2", "app.log.20210530T110847.000.gz"}
+	for _, file := range files {
+		suite.Nil(os.WriteFile(filepath.Join(suite.dir, file), []byte("message\n"), 0644))
+	}
+
+	backups, err := shipper.backups()
+	suite.Nil(err)
+	suite.Equal([]string{path + ".20210530T110847.000.gz", path + ".20210530T120847.000",
+		path + ".20210530T120847.000-2", path + ".20210530T120847.000-10.gz"}, backups)
+
+	suite.Nil(shipper.removeBackups())
+	backups, err = shipper.backups()
+	suite.Nil(err)
+	suite.Equal([]string{path + ".20210530T120847.000-2", path + ".20210530T120847.000-10.gz"}, backups)
+	for _, file := range files[:5] {
+		suite.FileExists(filepath.Join(suite.dir, file))
+	}
+	suite.NoFileExists(path + ".20210530T120847.000.gz")
+}
+
+func (suite *FileShipperTestSuite) TestReopenOnSighup() {
+
+	path := filepath.Join(suite.dir, "app.log")
+	shipper := NewFileShipper(path, 0, 0, 0, false).(*FileShipper)
+	defer shipper.Close()
+
+	suite.Nil(shipper.Ship(context.Background(), recordsForFileTest("message 1")))
+	suite.Nil(os.Rename(path, path+".1"))
+	process, err := os.FindProcess(os.Getpid())
+	suite.Nil(err)
+	suite.Nil(process.Signal(syscall.SIGHUP))
+	suite.Eventually(func() bool {
+		shipper.mutex.Lock()
+		defer shipper.mutex.Unlock()
+		return shipper.file == nil
+	}, time.Second, 10*time.Millisecond)
+
+	suite.Nil(shipper.Ship(context.Background(), recordsForFileTest("message 2")))
+	suite.Nil(shipper.Flush())
+	suite.Equal("message 1\n", suite.readFile(path+".1"))
+	suite.Equal("message 2\n", suite.readFile(path))
+}
+
+func (suite *FileShipperTestSuite) TestClose() {
+
+	path := filepath.Join(suite.dir, "app.log")
+	shipper := NewFileShipper(path, 0, 0, 0, false).(*FileShipper)
+	logger := NewLogger(Info, nil, shipper)
+
+	logger.Info("message 1")
+	suite.Nil(Close(logger))
+	suite.Nil(shipper.file)
+	suite.Nil(shipper.Close())
+	suite.Nil(Close(logger))
+	suite.Contains(suite.readFile(path), "message 1")
+}
+
+func (suite *FileShipperTestSuite) TestShipWithInvalidPath() {
+
+	path := filepath.Join(suite.readOnlyFile(), "app.log")
+	shipper := NewFileShipper(path, 0, 0, 0, false).(*FileShipper)
+	defer shipper.Close()
+
+	suite.NotNil(shipper.Ship(context.Background(), recordsForFileTest("message 1")))
+}
+
+func (suite *FileShipperTestSuite) readFile(path string) string {
+	content, err := os.ReadFile(path)
+	suite.Nil(err)
+	return string(content)
+}
+
+func (suite *FileShipperTestSuite) readGzipFile(path string) string {
+
+	file, err := os.Open(path)
+	suite.Nil(err)
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	suite.Nil(err)
+	content, err := io.ReadAll(reader)
+	suite.Nil(err)
+	return string(content)
+}
+
+// readOnlyFile creates a regular file, which can't be used as directory.
+func (suite *FileShipperTestSuite) readOnlyFile() string {
+	path := filepath.Join(suite.dir, "file")
+	suite.Nil(os.WriteFile(path, []byte{}, 0444))
+	return path
+}
+
+func recordsForFileTest(messages ...string) []Record {
+	records := []Record{}
+	for _, message := range messages {
+		record := newRecord(Info, message, newEmptyLogContext())
+		record.Payload = []byte(strings.TrimSpace(message))
+		records = append(records, record)
+	}
+	return records
+}
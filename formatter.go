@@ -1,7 +1,6 @@
 package log
 
 import (
-	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -11,9 +10,21 @@ func newDefaultFormatter() LogFormatter {
 	return &DefaultFormatter{}
 }
 
-// format converts passed log level and context using Sprintf and return a complete string together with passed message.
-func (formatter *DefaultFormatter) format(logLevel LogLevel, logContext LogContext, message string) string {
-	return fmt.Sprintf("%s: %s, Context: %+v", logLevel, message, logContext)
+// setTimestampFormat defines how the time of a record is rendered.
+func (formatter *DefaultFormatter) setTimestampFormat(timestampFormat TimestampFormat) {
+	formatter.timestamp = timestampFormat
+}
+
+// Format converts log level and context of passed record using Sprintf and return a complete string together with it's message.
+// The time of a record is prepended if a timestamp format has been defined, by default in RFC 3339 format.
+// A zero time is omitted.
+func (formatter *DefaultFormatter) Format(record Record) ([]byte, error) {
+
+	message := fmt.Sprintf("%s: %s, Context: %+v", record.Level, record.Message, record.Context)
+	if formatter.timestamp.isDefined() && !record.Time.IsZero() {
+		message = formatter.timestamp.format(record.Time.UTC(), time.RFC3339Nano) + " " + message
+	}
+	return []byte(message), nil
 }
 
 // newLogzioJsonFormatter returns a new LogzioJsonFormatter.
@@ -21,15 +32,42 @@ func newLogzioJsonFormatter() LogFormatter {
 	return &LogzioJsonFormatter{}
 }
 
-// format composes passed log level, context and message in a map and marshal it to JSON.
-func (formatter *LogzioJsonFormatter) format(logLevel LogLevel, logContext LogContext, message string) string {
+// setTimestampFormat defines how the time of a record is rendered.
+func (formatter *LogzioJsonFormatter) setTimestampFormat(timestampFormat TimestampFormat) {
+	formatter.timestamp = timestampFormat
+}
+
+// setCollisionPolicy defines how context values with reserved keys are handled.
+func (formatter *LogzioJsonFormatter) setCollisionPolicy(policy CollisionPolicy) {
+	formatter.collisions = policy
+}
+
+// setKeyMapping defines names of keys and a prefix for context values.
+func (formatter *LogzioJsonFormatter) setKeyMapping(keys *keyMapping) {
+	formatter.keys = keys
+}
+
+// Format composes log level, context and message of passed record in a map and marshal it to JSON.
+// Fields of the record context are rendered with their native JSON types. Keys are renamed
+// and context values are prefixed if there's a key mapping. Context values with the key of
+// log level, timestamp or message are handled according to the collision policy. A zero time is omitted.
+func (formatter *LogzioJsonFormatter) Format(record Record) ([]byte, error) {
 
-	ctxValues := logContext.values
-	ctxValues[LogCtxLogLevel] = logLevel.String()
-	ctxValues["@timestamp"] = time.Now().UTC().Format(LOGZIO_TIMESTAMP_FORMAT)
-	ctxValues[LogCtxMessage] = message
+	ctxValues := make(map[string]interface{})
+	for key, value := range record.Context.values {
+		formatter.keys.setContextValue(ctxValues, key, value)
+	}
+	for _, field := range record.Context.fields {
+		formatter.keys.setContextValue(ctxValues, field.Key, field.jsonValue())
+	}
+	reserved := map[string]interface{}{
+		formatter.keys.builtInKey(LogCtxLogLevel): record.Level.String(),
+		formatter.keys.builtInKey(LogCtxMessage):  record.Message,
+	}
+	if !record.Time.IsZero() {
+		reserved[formatter.keys.builtInKey("@timestamp")] = formatter.timestamp.value(record.Time.UTC(), LOGZIO_TIMESTAMP_FORMAT)
+	}
+	formatter.collisions.merge(ctxValues, reserved)
 
-	// Since we marshal string values only here, we'll omit the error
-	logContent, _ := json.Marshal(ctxValues)
-	return string(logContent)
+	return marshalWithFallback(ctxValues), nil
 }
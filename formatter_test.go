@@ -3,6 +3,7 @@ package log
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -22,7 +23,9 @@ func (suite *FomatterTestSuite) TestDefaultFormatter() {
 	context := suite.contextForTest()
 	message := "Test Message"
 	expextedLogMessage := "Error: Test Message, Context: namespace:FomatterTestSuite,timestamp:2021-05-30T12:08:47+02:00"
-	suite.Equal(expextedLogMessage, formatter.format(logLevel, context, message))
+	logMessage, err := formatter.Format(Record{Level: logLevel, Context: context, Message: message})
+	suite.Nil(err)
+	suite.Equal(expextedLogMessage, string(logMessage))
 }
 
 func (suite *FomatterTestSuite) TestLogzioJsonFormatter() {
@@ -32,10 +35,16 @@ func (suite *FomatterTestSuite) TestLogzioJsonFormatter() {
 	context := suite.contextForTest()
 	message := "Test Message"
 
-	logMessage := formatter.format(logLevel, context, message)
+	logContent, err := formatter.Format(Record{Time: time.Now(), Level: logLevel, Context: context, Message: message})
+	suite.Nil(err)
+	logMessage := string(logContent)
 	suite.True(strings.Contains(logMessage, "Error"))
 	suite.True(strings.Contains(logMessage, "@timestamp"))
 	suite.True(strings.Contains(logMessage, message))
+
+	logContent, err = formatter.Format(Record{Level: logLevel, Context: context, Message: message})
+	suite.Nil(err)
+	suite.False(strings.Contains(string(logContent), "@timestamp"))
 }
 
 func (suite *FomatterTestSuite) contextForTest() LogContext {
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// GELF_VERSION is the version of GELF used by GelfFormatter.
+const GELF_VERSION = "1.1"
+
+// gelfReservedFields contains all field names defined by GELF. They can not be used as additional fields.
+var gelfReservedFields = map[string]bool{
+	"version":       true,
+	"host":          true,
+	"short_message": true,
+	"full_message":  true,
+	"timestamp":     true,
+	"level":         true,
+	"facility":      true,
+	"line":          true,
+	"file":          true,
+	"_id":           true,
+}
+
+// gelfInvalidFieldChars matches all characters which are not allowed in a GELF field name.
+var gelfInvalidFieldChars = regexp.MustCompile(`[^\w\.\-]`)
+
+// newGelfFormatter returns a new GelfFormatter which uses hostname of current node as default host.
+func newGelfFormatter() LogFormatter {
+	hostname, _ := os.Hostname()
+	return &GelfFormatter{hostname: hostname}
+}
+
+// Format creates a GELF message for passed record. First line of a log message is used as
+// short_message, multi-line messages are added as full_message. Log level is converted to
+// syslog levels, Status is sent as notice. All context values and fields are added as additional fields.
+func (formatter *GelfFormatter) Format(record Record) ([]byte, error) {
+
+	message := make(map[string]interface{})
+	for key, value := range record.Context.values {
+		message[gelfFieldName(key)] = value
+	}
+	for _, field := range record.Context.fields {
+		message[gelfFieldName(field.Key)] = gelfValue(field)
+	}
+
+	shortMessage, _, isMultiLine := strings.Cut(record.Message, "\n")
+	message["version"] = GELF_VERSION
+	message["host"] = formatter.hostFor(record)
+	message["short_message"] = shortMessage
+	if isMultiLine {
+		message["full_message"] = record.Message
+	}
+	if !record.Time.IsZero() {
+		message["timestamp"] = float64(record.Time.UnixMilli()) / 1000
+	}
+	message["level"] = record.Level.SyslogLevel()
+	return marshalWithFallback(message), nil
+}
+
+// hostFor returns the hostname from context of passed record or hostname of this formatter.
+func (formatter *GelfFormatter) hostFor(record Record) string {
+
+	if hostname, ok := record.Context.lookup(LogCtxHostname); ok && hostname != "" {
+		return hostname
+	}
+	if formatter.hostname != "" {
+		return formatter.hostname
+	}
+	return "unknown"
+}
+
+// gelfFieldName converts passed key to the name of an additional field. Characters which are
+// not allowed are replaced by an underscore and a leading underscore is added.
+// Reserved field names get a second leading underscore, e.g. id will be __id.
+func gelfFieldName(key string) string {
+
+	fieldName := "_" + gelfInvalidFieldChars.ReplaceAllString(key, "_")
+	for gelfReservedFields[fieldName] {
+		fieldName = "_" + fieldName
+	}
+	return fieldName
+}
+
+// gelfValue returns the value of passed field as number or string, because
+// GELF supports these types for additional fields only.
+func gelfValue(field Field) interface{} {
+
+	switch field.Type {
+	case IntType, FloatType, DurationType:
+		return field.jsonValue()
+	default:
+		return field.text()
+	}
+}
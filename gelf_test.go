@@ -0,0 +1,97 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type GelfFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestGelfFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(GelfFormatterTestSuite))
+}
+
+func (suite *GelfFormatterTestSuite) TestFormat() {
+
+	logContext := newLogContext(map[string]string{
+		LogCtxHostname:  "host-1",
+		LogCtxNamespace: "billing",
+		"id":            "123",
+		"user name":     "john",
+	}).AppendFields(Int("count", 3), Bool("ok", false), Err(errors.New("failed")))
+	record := Record{
+		Time:    time.Date(2021, 5, 30, 12, 8, 47, 123456789, time.UTC),
+		Level:   Error,
+		Message: "Test Message",
+		Context: logContext,
+	}
+
+	logMessage, err := newGelfFormatter().Format(record)
+	suite.Nil(err)
+	expected := `{
+		"version": "1.1",
+		"host": "host-1",
+		"short_message": "Test Message",
+		"timestamp": 1622376527.123,
+		"level": 3,
+		"_hostname": "host-1",
+		"_namespace": "billing",
+		"__id": "123",
+		"_user_name": "john",
+		"_count": 3,
+		"_ok": "false",
+		"_error": "failed"
+	}`
+	suite.JSONEq(expected, string(logMessage))
+}
+
+func (suite *GelfFormatterTestSuite) TestMultiLineMessage() {
+
+	formatter := &GelfFormatter{hostname: "node-1"}
+	record := Record{
+		Time:    time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC),
+		Level:   Debug,
+		Message: "Test Message\nStack Trace",
+		Context: newEmptyLogContext(),
+	}
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	expected := `{
+		"version": "1.1",
+		"host": "node-1",
+		"short_message": "Test Message",
+		"full_message": "Test Message\nStack Trace",
+		"timestamp": 1622376527,
+		"level": 7
+	}`
+	suite.JSONEq(expected, string(logMessage))
+	suite.Equal("unknown", (&GelfFormatter{}).hostFor(record))
+}
+
+func (suite *GelfFormatterTestSuite) TestLevel() {
+
+	formatter := &GelfFormatter{hostname: "node-1"}
+	for logLevel, expected := range map[LogLevel]float64{Status: 5, Error: 3, Info: 6, Debug: 7} {
+		record := Record{Time: time.Now(), Level: logLevel, Message: "Test Message", Context: newEmptyLogContext()}
+		logMessage, err := formatter.Format(record)
+		suite.Nil(err)
+		message := make(map[string]interface{})
+		suite.Nil(json.Unmarshal(logMessage, &message))
+		suite.Equal(expected, message["level"], logLevel.String())
+	}
+}
+
+func (suite *GelfFormatterTestSuite) TestFieldNames() {
+
+	suite.Equal("_message", gelfFieldName("message"))
+	suite.Equal("__id", gelfFieldName("id"))
+	suite.Equal("_a.b-c_d", gelfFieldName("a.b-c d"))
+	suite.Equal("__level", gelfFieldName("_level"))
+}
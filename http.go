@@ -0,0 +1,217 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	config "github.com/tommzn/go-config"
+	secrets "github.com/tommzn/go-secrets"
+)
+
+// HTTP_METHOD is the default method used to ship logs to a HTTP endpoint.
+// Can be set by config: log.http.method
+const HTTP_METHOD = http.MethodPost
+
+// Body framings supported by HttpShipper.
+// Can be set by config: log.http.framing
+const (
+	// HTTP_FRAMING_NDJSON sends one payload per line.
+	HTTP_FRAMING_NDJSON = "ndjson"
+
+	// HTTP_FRAMING_JSON sends all payloads as a JSON array.
+	HTTP_FRAMING_JSON = "json"
+)
+
+// HTTP_TIMEOUT is the default timeout of a shipment request, including reading the response.
+// Can be set by config: log.http.timeout
+const HTTP_TIMEOUT = 10 * time.Second
+
+// NewHttpShipper returns a shipper which sends logs in batches to passed url. Headers are added
+// to each request, secretHeaders are header names with keys of secrets which are obtained
+// from passed secrets manager for each request. Framing can be ndjson or json, an unknown framing
+// is written to STDERR and ndjson is used instead. Batches are shipped with default settings used for Logz.io.
+func NewHttpShipper(url, method string, headers, secretHeaders map[string]string, framing string, secretsManager secrets.SecretsManager) LogShipper {
+
+	shipper := newHttpShipper(nil, secretsManager)
+	shipper.url = url
+	if method != "" {
+		shipper.method = strings.ToUpper(method)
+	}
+	if framing != "" {
+		shipper.framing = httpFramingByName(framing)
+	}
+	for name, value := range headers {
+		shipper.headers[name] = value
+	}
+	for name, secretKey := range secretHeaders {
+		shipper.secretHeaders[name] = secretKey
+	}
+	return shipper
+}
+
+// newHttpShipperFromConfig creates a new shipper for a HTTP endpoint.
+// Url is set by config: log.http.url
+// Headers can be set by config as list of maps with a name and either a value
+// or a key of a secret: log.http.headers
+// Status codes of successful requests can be set by config as comma separated list: log.http.successcodes
+// Timeout of requests can be set by config: log.http.timeout
+// Batch settings can be set by config, with the same defaults as for Logz.io: log.http.batchsize,
+// log.http.messagestacksize, log.http.shipmentstacksize, log.http.shipmenttimeout and log.http.messagereadtimeout
+func newHttpShipperFromConfig(conf config.Config, secretsManager secrets.SecretsManager) LogShipper {
+
+	shipper := newHttpShipper(conf, secretsManager)
+	shipper.url = *conf.Get("log.http.url", config.AsStringPtr(""))
+	shipper.method = strings.ToUpper(*conf.Get("log.http.method", config.AsStringPtr(HTTP_METHOD)))
+	shipper.framing = httpFramingByName(*conf.Get("log.http.framing", config.AsStringPtr(HTTP_FRAMING_NDJSON)))
+	for _, header := range conf.GetAsSliceOfMaps("log.http.headers") {
+		if header["name"] == "" {
+			continue
+		}
+		if secretKey, ok := header["secret"]; ok {
+			shipper.secretHeaders[header["name"]] = secretKey
+		} else {
+			shipper.headers[header["name"]] = header["value"]
+		}
+	}
+	if successCodes := conf.Get("log.http.successcodes", nil); successCodes != nil {
+		for _, code := range strings.Split(*successCodes, ",") {
+			if statusCode, err := strconv.Atoi(strings.TrimSpace(code)); err == nil {
+				shipper.successCodes = append(shipper.successCodes, statusCode)
+			}
+		}
+	}
+	return shipper
+}
+
+// newHttpShipper creates a shipper with default method and framing and timeout and batch settings
+// read from config, if passed.
+func newHttpShipper(conf config.Config, secretsManager secrets.SecretsManager) *HttpShipper {
+
+	timeout := HTTP_TIMEOUT
+	if conf != nil {
+		timeout = *conf.GetAsDuration("log.http.timeout", config.AsDurationPtr(HTTP_TIMEOUT))
+	}
+	shipper := &HttpShipper{
+		method:         HTTP_METHOD,
+		headers:        make(map[string]string),
+		secretHeaders:  make(map[string]string),
+		framing:        HTTP_FRAMING_NDJSON,
+		httpClient:     &http.Client{Timeout: timeout},
+		secretsManager: secretsManager,
+	}
+	shipper.batchShipper = newBatchShipperFromConfig(conf, "log.http", shipper.sendBatch)
+	return shipper
+}
+
+// httpFramingByName returns the framing for passed name. Unknown names are written to STDERR
+// and ndjson is returned for them.
+func httpFramingByName(name string) string {
+
+	switch framing := strings.ToLower(strings.TrimSpace(name)); framing {
+	case HTTP_FRAMING_NDJSON, HTTP_FRAMING_JSON:
+		return framing
+	default:
+		log.Printf("Unknown HTTP framing: %s, using %s", name, HTTP_FRAMING_NDJSON)
+		return HTTP_FRAMING_NDJSON
+	}
+}
+
+// sendBatch sends payloads of passed records in a single request to the endpoint.
+func (shipper *HttpShipper) sendBatch(messages []Record) error {
+
+	body, contentType := shipper.body(messages)
+	req, err := http.NewRequest(shipper.method, shipper.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range shipper.headers {
+		req.Header.Set(name, value)
+	}
+	for name, secretKey := range shipper.secretHeaders {
+		if shipper.secretsManager == nil {
+			return fmt.Errorf("Unable to obtain secret for header %s, no secrets manager", name)
+		}
+		value, err := shipper.secretsManager.Obtain(secretKey)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(name, *value)
+	}
+	return shipper.sendRequest(req)
+}
+
+// body returns the request body and it's content type for passed records, depending on framing.
+// For NDJSON each payload is terminated by a newline. For a JSON array, payloads which are
+// not valid JSON are added as string.
+func (shipper *HttpShipper) body(messages []Record) ([]byte, string) {
+
+	if shipper.framing != HTTP_FRAMING_JSON {
+		body := &bytes.Buffer{}
+		for _, message := range messages {
+			body.Write(message.Payload)
+			body.WriteByte('\n')
+		}
+		return body.Bytes(), "application/x-ndjson"
+	}
+
+	payloads := make([]json.RawMessage, 0, len(messages))
+	for _, message := range messages {
+		if json.Valid(message.Payload) {
+			payloads = append(payloads, json.RawMessage(message.Payload))
+		} else {
+			payload, _ := json.Marshal(string(message.Payload))
+			payloads = append(payloads, payload)
+		}
+	}
+	// Payloads are valid JSON or strings, so we'll omit the error
+	body, _ := json.Marshal(payloads)
+	return body, "application/json"
+}
+
+// sendRequest executes passed request and validates the status code of it's response.
+func (shipper *HttpShipper) sendRequest(request *http.Request) error {
+
+	resp, err := shipper.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return fmt.Errorf("HTTP shipment to %s, no response", shipper.url)
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close()
+	}
+	if shipper.isSuccess(resp.StatusCode) {
+		return nil
+	}
+	var responseBody string
+	if resp.Body != nil {
+		if bodyBytes, err := ioutil.ReadAll(resp.Body); err == nil {
+			responseBody = string(bodyBytes)
+		}
+	}
+	return fmt.Errorf("HTTP shipment to %s, %d: %s", shipper.url, resp.StatusCode, responseBody)
+}
+
+// isSuccess returns true if passed status code is one of the success codes,
+// or a 2xx status code if no success codes are defined.
+func (shipper *HttpShipper) isSuccess(statusCode int) bool {
+
+	if len(shipper.successCodes) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	for _, successCode := range shipper.successCodes {
+		if successCode == statusCode {
+			return true
+		}
+	}
+	return false
+}
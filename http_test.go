@@ -0,0 +1,180 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	secrets "github.com/tommzn/go-secrets"
+)
+
+type HttpShipperTestSuite struct {
+	suite.Suite
+}
+
+func TestHttpShipperTestSuite(t *testing.T) {
+	suite.Run(t, new(HttpShipperTestSuite))
+}
+
+func (suite *HttpShipperTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/http.yml")
+	logger := NewLoggerFromConfig(conf, suite.secretsManagerForTest())
+	suite.IsType(&LogzioJsonFormatter{}, logger.(*LogHandler).formatter)
+	suite.IsType(&HttpShipper{}, logger.(*LogHandler).shipper)
+
+	shipper := logger.(*LogHandler).shipper.(*HttpShipper)
+	suite.Equal("https://logs.example.com/ingest", shipper.url)
+	suite.Equal(http.MethodPut, shipper.method)
+	suite.Equal(HTTP_FRAMING_JSON, shipper.framing)
+	suite.Equal([]int{200, 202}, shipper.successCodes)
+	suite.Equal(map[string]string{"X-Source": "go-log"}, shipper.headers)
+	suite.Equal(map[string]string{"Authorization": "HTTP_LOG_TOKEN"}, shipper.secretHeaders)
+	suite.Equal(2, shipper.batchSize)
+	suite.Equal(3*time.Second, shipper.messageReadTimeout)
+	suite.Equal(5*time.Second, shipper.httpClient.(*http.Client).Timeout)
+	suite.Equal(cap(shipper.messageStack), MESSAGE_STACK_SIZE)
+
+	defaultShipper := NewHttpShipper("https://localhost/", "", nil, nil, "", nil).(*HttpShipper)
+	suite.Equal(HTTP_METHOD, defaultShipper.method)
+	suite.Equal(HTTP_FRAMING_NDJSON, defaultShipper.framing)
+	suite.Equal(LOGZIO_BATCH_SIZE, defaultShipper.batchSize)
+	suite.Equal(HTTP_TIMEOUT, defaultShipper.httpClient.(*http.Client).Timeout)
+}
+
+func (suite *HttpShipperTestSuite) TestFramingByName() {
+
+	suite.Equal(HTTP_FRAMING_JSON, httpFramingByName("JSON"))
+	suite.Equal(HTTP_FRAMING_NDJSON, httpFramingByName("ndjson"))
+	suite.Equal(HTTP_FRAMING_NDJSON, httpFramingByName("jsonl"))
+	suite.Equal(HTTP_FRAMING_NDJSON, NewHttpShipper("https://localhost/", "", nil, nil, "jsonl", nil).(*HttpShipper).framing)
+}
+
+func (suite *HttpShipperTestSuite) TestShipNdjson() {
+
+	shipper := suite.shipperForTest(HTTP_FRAMING_NDJSON)
+	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 204}
+	suite.Nil(shipper.Ship(context.Background(), recordsForHttpTest(`{"message":"one"}`, `{"message":"two"}`)))
+	suite.Nil(shipper.Flush())
+
+	requests := shipper.httpClient.(*testClient).recordedRequests()
+	suite.Len(requests, 1)
+	suite.Equal(http.MethodPost, requests[0].Method)
+	suite.Equal("https://localhost/ingest", requests[0].URL.String())
+	suite.Equal("application/x-ndjson", requests[0].Header.Get("Content-Type"))
+	suite.Equal("go-log", requests[0].Header.Get("X-Source"))
+	suite.Equal("Bearer <Token>", requests[0].Header.Get("Authorization"))
+	suite.Equal("{\"message\":\"one\"}\n{\"message\":\"two\"}\n", suite.requestBody(requests[0]))
+}
+
+func (suite *HttpShipperTestSuite) TestShipJsonArray() {
+
+	shipper := suite.shipperForTest(HTTP_FRAMING_JSON)
+	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 200}
+	suite.Nil(shipper.Ship(context.Background(), recordsForHttpTest(`{"message":"one"}`, "Info: two")))
+	suite.Nil(shipper.Flush())
+
+	requests := shipper.httpClient.(*testClient).recordedRequests()
+	suite.Len(requests, 1)
+	suite.Equal("application/json", requests[0].Header.Get("Content-Type"))
+	suite.Equal(`[{"message":"one"},"Info: two"]`, suite.requestBody(requests[0]))
+}
+
+func (suite *HttpShipperTestSuite) TestShipInBatches() {
+
+	shipper := suite.shipperForTest(HTTP_FRAMING_NDJSON)
+	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 200}
+	suite.Nil(shipper.Ship(context.Background(), recordsForHttpTest("1", "2", "3", "4", "5")))
+	suite.Eventually(func() bool { return len(shipper.httpClient.(*testClient).recordedRequests()) == 1 }, time.Second, 10*time.Millisecond)
+	suite.Nil(shipper.Flush())
+
+	suite.Len(shipper.httpClient.(*testClient).recordedRequests(), 2)
+}
+
+func (suite *HttpShipperTestSuite) TestShipEmptyBatch() {
+
+	shipper := suite.shipperForTest(HTTP_FRAMING_NDJSON)
+	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 400}
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	shipper.shipMessages(wg, []Record{})
+	wg.Wait()
+
+	suite.Len(shipper.httpClient.(*testClient).recordedRequests(), 0)
+}
+
+func (suite *HttpShipperTestSuite) TestShipmentErrors() {
+
+	var failedRecords []Record
+	var shipmentErrors []error
+	shipper := suite.shipperForTest(HTTP_FRAMING_NDJSON)
+	shipper.OnError(func(records []Record, err error) {
+		failedRecords = append(failedRecords, records...)
+		shipmentErrors = append(shipmentErrors, err)
+	})
+
+	shipper.successCodes = []int{202}
+	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 200}
+	suite.Nil(shipper.Ship(context.Background(), recordsForHttpTest("1")))
+	suite.Nil(shipper.Flush())
+	suite.Len(failedRecords, 1)
+	suite.Contains(shipmentErrors[0].Error(), "200")
+
+	shipper.httpClient.(*testClient).err = errors.New("connection refused")
+	suite.Nil(shipper.Ship(context.Background(), recordsForHttpTest("2")))
+	suite.Nil(shipper.Flush())
+	suite.Len(failedRecords, 2)
+	suite.EqualError(shipmentErrors[1], "connection refused")
+
+	shipper.httpClient.(*testClient).err = nil
+	shipper.secretsManager = secrets.NewStaticSecretsManager(make(map[string]string))
+	suite.Nil(shipper.Ship(context.Background(), recordsForHttpTest("3")))
+	suite.Nil(shipper.Flush())
+	suite.Len(failedRecords, 3)
+	suite.Len(shipper.httpClient.(*testClient).recordedRequests(), 2)
+}
+
+func (suite *HttpShipperTestSuite) TestSuccessCodes() {
+
+	shipper := suite.shipperForTest(HTTP_FRAMING_NDJSON)
+	suite.True(shipper.isSuccess(200))
+	suite.True(shipper.isSuccess(299))
+	suite.False(shipper.isSuccess(301))
+	suite.False(shipper.isSuccess(500))
+
+	shipper.successCodes = []int{200, 409}
+	suite.True(shipper.isSuccess(409))
+	suite.False(shipper.isSuccess(201))
+}
+
+func (suite *HttpShipperTestSuite) shipperForTest(framing string) *HttpShipper {
+	shipper := NewHttpShipper("https://localhost/ingest", "", map[string]string{"X-Source": "go-log"},
+		map[string]string{"Authorization": "HTTP_LOG_TOKEN"}, framing, suite.secretsManagerForTest()).(*HttpShipper)
+	shipper.httpClient = newHttpTestClient(nil, nil)
+	shipper.messageReadTimeout = 100 * time.Millisecond
+	shipper.batchSize = 3
+	return shipper
+}
+
+func (suite *HttpShipperTestSuite) secretsManagerForTest() secrets.SecretsManager {
+	return secrets.NewStaticSecretsManager(map[string]string{"HTTP_LOG_TOKEN": "Bearer <Token>"})
+}
+
+func (suite *HttpShipperTestSuite) requestBody(request *http.Request) string {
+	body, err := ioutil.ReadAll(request.Body)
+	suite.Nil(err)
+	return string(body)
+}
+
+func recordsForHttpTest(payloads ...string) []Record {
+	records := []Record{}
+	for _, payload := range payloads {
+		records = append(records, Record{Level: Info, Message: payload, Payload: []byte(payload)})
+	}
+	return records
+}
@@ -3,6 +3,7 @@ package log
 import (
 	"context"
 	"net/http"
+	"time"
 )
 
 // Logger is an infterface for different types of logger.
@@ -11,6 +12,14 @@ type Logger interface {
 	// WithContext sets a given log context.
 	WithContext(context.Context)
 
+	// With returns a child logger with passed fields added to it's log context.
+	// The current logger is not changed.
+	With(fields ...Field) Logger
+
+	// Named returns a child logger with passed name appended to it's namespace.
+	// The current logger is not changed.
+	Named(name string) Logger
+
 	// Statusf logs a formated message with log level Status.
 	Statusf(message string, v ...interface{})
 
@@ -41,6 +50,27 @@ type Logger interface {
 	// Logs passed message with given log level.
 	Log(logLevel LogLevel, v ...interface{})
 
+	// Statusw logs a message with log level Status and passed key/value pairs as fields.
+	Statusw(message string, keysAndValues ...interface{})
+
+	// Errorw logs a message with log level Error and passed key/value pairs as fields.
+	Errorw(message string, keysAndValues ...interface{})
+
+	// Infow logs a message with log level Info and passed key/value pairs as fields.
+	Infow(message string, keysAndValues ...interface{})
+
+	// Debugw logs a message with log level Debug and passed key/value pairs as fields.
+	Debugw(message string, keysAndValues ...interface{})
+
+	// Logw logs a message with given log level and passed key/value pairs as fields.
+	Logw(logLevel LogLevel, message string, keysAndValues ...interface{})
+
+	// Metric logs a single metric with passed fields as dimensions.
+	Metric(name string, value float64, unit MetricUnit, dimensions ...Field)
+
+	// Metrics logs all passed metrics in one record with passed fields as dimensions.
+	Metrics(metrics []Metric, dimensions ...Field)
+
 	// FLush tells the log shipper to cleat it's internal message queue.
 	Flush()
 }
@@ -48,21 +78,44 @@ type Logger interface {
 // LogShipper will take care of sending logs to a defined target.
 type LogShipper interface {
 
-	// Send will process given message. Depending on log shipper implementation
-	// this can lead to an immediate shippment or a shiiper can queue messages
-	// to deliver them in a batch.
-	send(string)
+	// Ship will process given records, using the formatted payload of each record.
+	// Depending on log shipper implementation this can lead to an immediate shippment
+	// or a shipper can queue records to deliver them in a batch.
+	Ship(ctx context.Context, records []Record) error
 
 	// Flush clear internal buffer.
 	// Depending on the logger this can include writing to a remote destination.
-	flush()
+	Flush() error
 }
 
-// LogFormatter will convert passed log values into a suitable log message.
+// AsyncLogShipper is a shipper which delivers records in background, so errors
+// can not be returned by Ship. Instead they're passed to a registered error handler.
+type AsyncLogShipper interface {
+	LogShipper
+
+	// OnError registers a handler which is called with all records of a failed shipment.
+	OnError(handler func(records []Record, err error))
+}
+
+// LogFormatter will convert passed log record into a suitable log message.
 type LogFormatter interface {
 
-	// Format create a log message from given values.
-	format(LogLevel, LogContext, string) string
+	// Format create a log message from given record.
+	Format(record Record) ([]byte, error)
+}
+
+// Clock provides current time. It's used to create timestamps of records and
+// can be replaced to get deterministic timestamps, e.g. in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// LogHook is called for each record a logger creates, before it's formatted and shipped.
+type LogHook interface {
+
+	// Fire is called with each record. Hooks must not keep a reference to
+	// the context of a record to modify it later.
+	Fire(record Record) error
 }
 
 // httpClient is an interface for a HTTP client.
@@ -0,0 +1,102 @@
+package log
+
+import (
+	"strings"
+
+	config "github.com/tommzn/go-config"
+)
+
+// keyMappingKeys are built-in keys and keys of well known context values which can be renamed.
+var keyMappingKeys = []string{
+	LogCtxLogLevel, LogCtxMessage, "@timestamp", LogCtxRequestId, LogCtxNamespace, LogCtxDomain, LogCtxHostname,
+	LogCtxIp, LogCtxK8sNode, LogCtxK8sPod, LogCtxError, LogCtxTraceId, LogCtxSpanId,
+}
+
+// newKeyMappingFromConfig returns a key mapping if there're names of keys or a prefix defined by config, e.g.
+//
+//	log:
+//	  formatter:
+//	    type: json
+//	    fields:
+//	      loglevel: level
+//	      message: msg
+//	      requestid: request_id
+//	      tenant: tenant_id
+//	    customkeys: tenant
+//	    prefix: ctx.
+//
+// Names can be defined for built-in keys, like loglevel, message or @timestamp, and for keys of
+// well known context values, e.g. requestid or hostname. Keys of other context values and fields
+// have to be listed in log.formatter.customkeys as comma separated list, because names are read
+// once, here. Keys are case insensitive, because config keys are, and can't contain dots.
+// All other keys of context values and fields are prefixed by log.formatter.prefix, a prefix
+// ending with a dot adds them to a nested object, e.g. {"ctx": {"requestid": "..."}}.
+// A key mapping is used by LogzioJsonFormatter only, other formatters use keys defined by their format.
+// Returns nil if neither names nor a prefix are defined.
+func newKeyMappingFromConfig(conf config.Config) *keyMapping {
+
+	keys := append([]string{}, keyMappingKeys...)
+	if customKeys := conf.Get("log.formatter.customkeys", nil); customKeys != nil {
+		for _, key := range strings.Split(*customKeys, ",") {
+			if key = strings.ToLower(strings.TrimSpace(key)); key != "" && !strings.Contains(key, ".") {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	names := make(map[string]string)
+	for _, key := range keys {
+		if name := conf.Get("log.formatter.fields."+key, nil); name != nil && *name != "" {
+			names[key] = *name
+		}
+	}
+	prefix := conf.Get("log.formatter.prefix", config.AsStringPtr(""))
+	if len(names) == 0 && *prefix == "" {
+		return nil
+	}
+	return &keyMapping{names: names, prefix: *prefix}
+}
+
+// name returns the name defined for passed key, if there's any.
+func (mapping *keyMapping) name(key string) (string, bool) {
+
+	if mapping == nil {
+		return "", false
+	}
+	name, ok := mapping.names[strings.ToLower(key)]
+	return name, ok
+}
+
+// builtInKey returns the name defined for passed built-in key, e.g. loglevel, or the key itself.
+func (mapping *keyMapping) builtInKey(key string) string {
+	if name, ok := mapping.name(key); ok {
+		return name
+	}
+	return key
+}
+
+// setContextValue adds passed value of a context value or field to given document. A value is added
+// with the name defined for its key, or with the prefixed key if there's no name for it.
+func (mapping *keyMapping) setContextValue(document map[string]interface{}, key string, value interface{}) {
+
+	if name, ok := mapping.name(key); ok {
+		document[name] = value
+		return
+	}
+	if mapping == nil || mapping.prefix == "" {
+		document[key] = value
+		return
+	}
+	if !strings.HasSuffix(mapping.prefix, ".") {
+		document[mapping.prefix+key] = value
+		return
+	}
+
+	objectKey := strings.TrimSuffix(mapping.prefix, ".")
+	object, ok := document[objectKey].(map[string]interface{})
+	if !ok {
+		object = make(map[string]interface{})
+		document[objectKey] = object
+	}
+	object[key] = value
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type KeyMappingTestSuite struct {
+	suite.Suite
+}
+
+func TestKeyMappingTestSuite(t *testing.T) {
+	suite.Run(t, new(KeyMappingTestSuite))
+}
+
+func (suite *KeyMappingTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/keymapping.yml")
+	keys := newKeyMappingFromConfig(conf)
+	suite.NotNil(keys)
+	suite.Equal("ctx.", keys.prefix)
+
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.NotNil(logger.(*LogHandler).formatter.(*LogzioJsonFormatter).keys)
+
+	suite.Nil(newKeyMappingFromConfig(loadConfigFromFile("config/timestamp.yml")))
+}
+
+func (suite *KeyMappingTestSuite) TestName() {
+
+	keys := newKeyMappingFromConfig(loadConfigFromFile("config/keymapping.yml"))
+
+	name, ok := keys.name(LogCtxLogLevel)
+	suite.True(ok)
+	suite.Equal("level", name)
+	name, ok = keys.name("@timestamp")
+	suite.True(ok)
+	suite.Equal("timestamp", name)
+	_, ok = keys.name("domain")
+	suite.False(ok)
+	_, ok = keys.name("http.status")
+	suite.False(ok)
+	name, ok = keys.name("Tenant")
+	suite.True(ok)
+	suite.Equal("tenant_id", name)
+
+	// Names of custom keys are available if they're listed in customkeys, only.
+	_, ok = keys.name("userid")
+	suite.False(ok)
+	suite.Equal(map[string]string{LogCtxLogLevel: "level", LogCtxMessage: "msg", "@timestamp": "timestamp",
+		LogCtxRequestId: "request_id", "tenant": "tenant_id"}, keys.names)
+
+	var noKeys *keyMapping
+	suite.Equal(LogCtxMessage, noKeys.builtInKey(LogCtxMessage))
+}
+
+func (suite *KeyMappingTestSuite) TestSetContextValue() {
+
+	document := make(map[string]interface{})
+	(&keyMapping{prefix: "ctx_"}).setContextValue(document, "domain", "billing")
+	suite.Equal(map[string]interface{}{"ctx_domain": "billing"}, document)
+
+	document = make(map[string]interface{})
+	keys := &keyMapping{prefix: "ctx."}
+	keys.setContextValue(document, "domain", "billing")
+	keys.setContextValue(document, "count", 3)
+	suite.Equal(map[string]interface{}{"ctx": map[string]interface{}{"domain": "billing", "count": 3}}, document)
+}
+
+func (suite *KeyMappingTestSuite) TestFormatWithKeyMapping() {
+
+	conf := loadConfigFromFile("config/keymapping.yml")
+	formatter := newLogzioJsonFormatter().(*LogzioJsonFormatter)
+	formatter.setKeyMapping(newKeyMappingFromConfig(conf))
+
+	logContext := newLogContext(map[string]string{LogCtxRequestId: "req-1", LogCtxDomain: "billing"}).
+		AppendFields(Int("count", 3), String("tenant", "acme"))
+	record := newRecord(Info, "Test Message", logContext)
+	record.Time = time.Date(2021, 5, 30, 12, 8, 47, 123456789, time.UTC)
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	expected := `{
+		"level": "Info",
+		"msg": "Test Message",
+		"timestamp": "2021-05-30T12:08:47.123Z",
+		"request_id": "req-1",
+		"tenant_id": "acme",
+		"ctx": {"domain": "billing", "count": 3}
+	}`
+	suite.JSONEq(expected, string(logMessage))
+}
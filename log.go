@@ -2,12 +2,16 @@
 package log
 
 import (
+	"os"
 	"strings"
 
 	config "github.com/tommzn/go-config"
 	secrets "github.com/tommzn/go-secrets"
 )
 
+// ENV_LOGFORMATTER defines the environment variable which can be used to select a formatter, e.g. console.
+const ENV_LOGFORMATTER = "LOGFORMATTER"
+
 // NewLogger returns a new logger with passed log level, formatter and shipper.
 // If you omit formatter and shipper the DefaultFormatter and StdoutShipper will be used.
 func NewLogger(logLevel LogLevel, formatter LogFormatter, shipper LogShipper) Logger {
@@ -29,22 +33,167 @@ func NewLogger(logLevel LogLevel, formatter LogFormatter, shipper LogShipper) Lo
 // NewLoggerFromConfig returns a new logger created depending on passed config.
 func NewLoggerFromConfig(conf config.Config, secretsManager secrets.SecretsManager) Logger {
 
+	formatter, shipper := formatterAndShipperFromConfig(conf, secretsManager)
+	return &LogHandler{
+		logLevel:  logLevelForShipper(conf, shipper),
+		context:   newEmptyLogContext(),
+		formatter: formatter,
+		shipper:   shipper,
+	}
+}
+
+// formatterAndShipperFromConfig creates a formatter and a shipper depending on passed config.
+// If there're outputs defined at log.outputs, a MultiShipper is created for them. Records are
+// formatted by the formatter of each output or sink, the returned formatter isn't used in this case.
+func formatterAndShipperFromConfig(conf config.Config, secretsManager secrets.SecretsManager) (LogFormatter, LogShipper) {
+
+	if outputs := conf.GetAsSliceOfMaps("log.outputs"); len(outputs) > 0 {
+		return newDefaultFormatter(), newMultiShipperFromConfig(conf, outputs, secretsManager)
+	}
+	if sinks := conf.GetAsSliceOfMaps("log.sinks"); len(sinks) > 0 {
+		return newDefaultFormatter(), newRouterShipperFromConfig(conf, sinks, secretsManager)
+	}
+
+	outputConfig := make(map[string]string)
+	if shipperType := conf.Get("log.shipper", nil); shipperType != nil {
+		outputConfig["shipper"] = *shipperType
+	}
+	if formatterName := formatterNameFromConfig(conf); formatterName != "" {
+		outputConfig["formatter"] = formatterName
+	}
+	formatter, shipper := formatterAndShipperByName(conf, outputConfig, secretsManager)
+	return formatter, newFallbackShipperFromConfig(conf, shipper, secretsManager)
+}
+
+// formatterAndShipperByName creates a shipper and a formatter with names defined in passed output config.
+// If there's no formatter name, the default formatter for a shipper will be used.
+// Unknown names will fall back to DefaultFormatter and StdoutShipper.
+// For Logz.io an output config can define a different url and token key, for files a different path,
+// for syslog a different network and address and for HTTP a different url.
+// A timestamp format defined at log.formatter.timestamp, a collision policy defined at log.formatter.collision
+// and a key mapping defined at log.formatter.fields and log.formatter.prefix are applied to all formatters
+// which support them. A key mapping is supported by LogzioJsonFormatter only, a timestamp format
+// is ignored by GELF, syslog and OTLP formatters.
+func formatterAndShipperByName(conf config.Config, outputConfig map[string]string, secretsManager secrets.SecretsManager) (LogFormatter, LogShipper) {
+
 	var formatter LogFormatter
 	var shipper LogShipper
 
-	shipperType := conf.Get("log.shipper", nil)
-	if shipperType != nil && strings.ToLower(*shipperType) == "logzio" {
+	switch strings.ToLower(outputConfig["shipper"]) {
+	case "logzio":
+		formatter = newLogzioJsonFormatter()
+		logzioShipper := newLogzioShipper(conf, secretsManager).(*LogzioShipper)
+		if url, ok := outputConfig["url"]; ok {
+			logzioShipper.logzioUrl = url
+		}
+		if tokenKey, ok := outputConfig["tokenkey"]; ok {
+			logzioShipper.tokenKey = tokenKey
+		}
+		shipper = logzioShipper
+	case "syslog":
+		formatter = newSyslogFormatter(conf, false)
+		syslogShipper := newSyslogShipperFromConfig(conf).(*SyslogShipper)
+		if network, ok := outputConfig["network"]; ok {
+			syslogShipper.network = network
+		}
+		if address, ok := outputConfig["address"]; ok {
+			syslogShipper.address = address
+		}
+		shipper = syslogShipper
+	case "http":
 		formatter = newLogzioJsonFormatter()
-		shipper = newLogzioShipper(conf, secretsManager)
-	} else {
+		httpShipper := newHttpShipperFromConfig(conf, secretsManager).(*HttpShipper)
+		if url, ok := outputConfig["url"]; ok {
+			httpShipper.url = url
+		}
+		shipper = httpShipper
+	case "file":
+		formatter = newDefaultFormatter()
+		fileShipper := newFileShipperFromConfig(conf).(*FileShipper)
+		if path, ok := outputConfig["path"]; ok {
+			fileShipper.path = path
+		}
+		shipper = fileShipper
+	default:
 		formatter = newDefaultFormatter()
 		shipper = newStdoutShipper()
 	}
 
-	return &LogHandler{
-		logLevel:  LogLevelFromConfig(conf),
-		context:   newEmptyLogContext(),
-		formatter: formatter,
-		shipper:   shipper,
+	switch strings.ToLower(outputConfig["formatter"]) {
+	case "default":
+		formatter = newDefaultFormatter()
+	case "logzio", "json":
+		formatter = newLogzioJsonFormatter()
+	case "logfmt":
+		formatter = newLogfmtFormatter()
+	case "ecs":
+		formatter = newEcsJsonFormatter(conf)
+	case "gelf":
+		formatter = newGelfFormatter()
+	case "console":
+		formatter = newConsoleFormatter(conf)
+	case "datadog":
+		formatter = newDatadogJsonFormatter(conf)
+	case "otlp", "opentelemetry":
+		formatter = newOtlpJsonFormatter(conf)
+	case "emf":
+		formatter = newEmfFormatter(conf)
+	case "gcp", "cloudlogging":
+		formatter = newCloudLoggingFormatter(conf)
+	case "template":
+		formatter = newTemplateFormatterFromConfig(conf)
+	case "syslog", "rfc5424":
+		formatter = newSyslogFormatter(conf, false)
+	case "rfc3164":
+		formatter = newSyslogFormatter(conf, true)
+	}
+	if timestampFormat, ok := timestampFormatFromConfig(conf); ok {
+		if timestampFormatter, ok := formatter.(interface{ setTimestampFormat(TimestampFormat) }); ok {
+			timestampFormatter.setTimestampFormat(timestampFormat)
+		}
+	}
+	if policy, ok := collisionPolicyFromConfig(conf); ok {
+		if collisionFormatter, ok := formatter.(interface{ setCollisionPolicy(CollisionPolicy) }); ok {
+			collisionFormatter.setCollisionPolicy(policy)
+		}
+	}
+	if keys := newKeyMappingFromConfig(conf); keys != nil {
+		if keyMappingFormatter, ok := formatter.(interface{ setKeyMapping(*keyMapping) }); ok {
+			keyMappingFormatter.setKeyMapping(keys)
+		}
+	}
+	return formatter, shipper
+}
+
+// formatterNameFromConfig returns the name of a formatter defined by log.formatter. Formatters with
+// additional settings can be defined by log.formatter.type instead. A formatter defined by
+// environment variable ENV_LOGFORMATTER takes precedence over config.
+func formatterNameFromConfig(conf config.Config) string {
+
+	if formatterName, ok := os.LookupEnv(ENV_LOGFORMATTER); ok && formatterName != "" {
+		return formatterName
+	}
+	if formatterType := conf.Get("log.formatter.type", nil); formatterType != nil {
+		return *formatterType
+	}
+	if conf.Get("log.formatter.template", nil) != nil {
+		return "template"
+	}
+	if formatterName := conf.Get("log.formatter", nil); formatterName != nil {
+		return *formatterName
+	}
+	return ""
+}
+
+// logLevelForShipper returns the log level defined at log.loglevel. If there's no log level
+// defined and passed shipper is a MultiShipper or a RouterShipper, the most verbose log level
+// of all outputs is returned, so each output gets all records it accepts.
+func logLevelForShipper(conf config.Config, shipper LogShipper) LogLevel {
+
+	if conf.Get("log.loglevel", nil) == nil {
+		if multiLevelShipper, ok := shipper.(interface{ maxLogLevel() LogLevel }); ok {
+			return multiLevelShipper.maxLogLevel()
+		}
 	}
+	return LogLevelFromConfig(conf)
 }
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// newLogfmtFormatter returns a new LogfmtFormatter.
+func newLogfmtFormatter() LogFormatter {
+	return &LogfmtFormatter{}
+}
+
+// setTimestampFormat defines how the time of a record is rendered.
+func (formatter *LogfmtFormatter) setTimestampFormat(timestampFormat TimestampFormat) {
+	formatter.timestamp = timestampFormat
+}
+
+// Format creates a logfmt line for passed record. It starts with time, level, message and namespace,
+// followed by all other context values and fields in alphabetical order of their keys.
+// Values are quoted if they contain spaces, quotes, equal signs or control characters.
+func (formatter *LogfmtFormatter) Format(record Record) ([]byte, error) {
+
+	builder := &strings.Builder{}
+	if !record.Time.IsZero() {
+		writeLogfmtPair(builder, "time", formatter.timestamp.format(record.Time.UTC(), time.RFC3339Nano))
+	}
+	writeLogfmtPair(builder, "level", strings.ToLower(record.Level.String()))
+	writeLogfmtPair(builder, "msg", record.Message)
+	if record.Namespace != "" {
+		writeLogfmtPair(builder, LogCtxNamespace, record.Namespace)
+	}
+
+	values := make(map[string]string)
+	for key, value := range record.Context.values {
+		values[key] = value
+	}
+	for _, field := range record.Context.fields {
+		values[field.Key] = field.text()
+	}
+	delete(values, LogCtxNamespace)
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		writeLogfmtPair(builder, key, values[key])
+	}
+	return []byte(builder.String()), nil
+}
+
+// writeLogfmtPair appends passed key and value separated by an equal sign to given builder.
+func writeLogfmtPair(builder *strings.Builder, key, value string) {
+
+	if builder.Len() > 0 {
+		builder.WriteByte(' ')
+	}
+	builder.WriteString(logfmtKey(key))
+	builder.WriteByte('=')
+	if logfmtNeedsQuotes(value) {
+		builder.WriteString(strconv.Quote(value))
+	} else {
+		builder.WriteString(value)
+	}
+}
+
+// logfmtKey replaces all characters which are not allowed in a logfmt key by an underscore.
+func logfmtKey(key string) string {
+
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtNeedsQuotes returns true if passed value is empty or contains a character
+// which requires quoting.
+func logfmtNeedsQuotes(value string) bool {
+
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LogfmtFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestLogfmtFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(LogfmtFormatterTestSuite))
+}
+
+func (suite *LogfmtFormatterTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/logfmt.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&LogfmtFormatter{}, logger.(*LogHandler).formatter)
+	suite.IsType(&StdoutShipper{}, logger.(*LogHandler).shipper)
+}
+
+func (suite *LogfmtFormatterTestSuite) TestFormat() {
+
+	logContext := newLogContext(map[string]string{
+		LogCtxNamespace: "billing",
+		LogCtxRequestId: "req-1",
+		"path":          "/api/v1",
+	}).AppendFields(Int("count", 3), Err(errors.New("request failed")), String("path", "/api/v2"))
+	record := Record{
+		Time:      time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC),
+		Level:     Error,
+		Message:   "Test Message",
+		Context:   logContext,
+		Namespace: "billing",
+	}
+
+	logMessage, err := newLogfmtFormatter().Format(record)
+	suite.Nil(err)
+	suite.Equal(`time=2021-05-30T12:08:47Z level=error msg="Test Message" namespace=billing count=3 error="request failed" path=/api/v2 requestid=req-1`, string(logMessage))
+}
+
+func (suite *LogfmtFormatterTestSuite) TestQuoting() {
+
+	logContext := newEmptyLogContext().AppendFields(
+		String("empty", ""),
+		String("quote", `say "hi"`),
+		String("equal", "a=b"),
+		String("backslash", `c:\tmp`),
+		String("unicode", "grüße"),
+		String("invalid key", "val"))
+	record := Record{Time: time.Date(2021, 5, 30, 12, 8, 47, 0, time.UTC), Level: Info, Message: "line 1\nline 2", Context: logContext}
+
+	logMessage, err := newLogfmtFormatter().Format(record)
+	suite.Nil(err)
+	suite.Equal(`time=2021-05-30T12:08:47Z level=info msg="line 1\nline 2" backslash="c:\\tmp" empty="" equal="a=b" invalid_key=val quote="say \"hi\"" unicode=grüße`, string(logMessage))
+}
@@ -3,6 +3,8 @@ package log
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
 )
 
 // LogHandler provides methods to log messges with different log level
@@ -12,26 +14,106 @@ type LogHandler struct {
 	context   LogContext
 	formatter LogFormatter
 	shipper   LogShipper
+	hooks     []LogHook
+	clock     Clock
 }
 
 // logf will format given log message.
 func (logger *LogHandler) logf(logLevel LogLevel, message string, v ...interface{}) {
-	logger.log(logLevel, fmt.Sprintf(message, v...))
+
+	if logger.logLevel >= logLevel {
+		logger.ship(logger.newRecord(logLevel, fmt.Sprintf(message, v...), logger.context))
+	}
 }
 
 // log will create a log message with given values.
 func (logger *LogHandler) log(logLevel LogLevel, v ...interface{}) {
 
 	if logger.logLevel >= logLevel {
-		logger.shipper.send(logger.formatter.format(logLevel, logger.context, fmt.Sprint(v...)))
+		logger.ship(logger.newRecord(logLevel, fmt.Sprint(v...), logger.context))
+	}
+}
+
+// logw will create a log message with given key/value pairs as additional fields.
+func (logger *LogHandler) logw(logLevel LogLevel, message string, keysAndValues []interface{}) {
+
+	if logger.logLevel >= logLevel {
+		logContext := logger.context.AppendFields(fieldsFromKeysAndValues(keysAndValues)...)
+		logger.ship(logger.newRecord(logLevel, message, logContext))
+	}
+}
+
+// newRecord creates a record for passed values with the code calling a logger method as caller.
+func (logger *LogHandler) newRecord(logLevel LogLevel, message string, logContext LogContext) Record {
+
+	record := newRecord(logLevel, message, logContext)
+	record.Time = nowFrom(logger.clock)
+	// Skip this method, internal log method and the logger method called by client code.
+	record.Caller = callerAt(3)
+	return record
+}
+
+// ship passes given record to all hooks, formats it and passes it to the log shipper.
+// Errors are written to STDERR, because there's no other way to report them.
+func (logger *LogHandler) ship(record Record) {
+
+	for _, hook := range logger.hooks {
+		if err := hook.Fire(record); err != nil {
+			log.Println(err)
+		}
+	}
+	if err := formatAndShip(context.Background(), logger.formatter, logger.shipper, record); err != nil {
+		log.Println(err)
 	}
 }
 
+// formatAndShip uses passed formatter to create the payload for given record
+// and passes the record to a shipper afterwards. Records are not formatted if
+// the shipper formats them itself, e.g. by a formatter for each output.
+func formatAndShip(ctx context.Context, formatter LogFormatter, shipper LogShipper, record Record) error {
+
+	if formattingShipper, ok := shipper.(interface{ formatsRecords() bool }); !ok || !formattingShipper.formatsRecords() {
+		payload, err := formatter.Format(record)
+		if err != nil {
+			return err
+		}
+		record.Payload = payload
+	}
+	return shipper.Ship(ctx, []Record{record})
+}
+
 // WithContext applies the log context.
 func (logger *LogHandler) WithContext(ctx context.Context) {
 	logger.context = getLogContext(ctx)
 }
 
+// With returns a new logger which shares formatter and shipper with current logger
+// and uses a copy of current log context with passed fields added.
+func (logger *LogHandler) With(fields ...Field) Logger {
+	return logger.withLogContext(logger.context.AppendFields(fields...))
+}
+
+// Named returns a new logger with passed name appended to current namespace, separated by a dot.
+func (logger *LogHandler) Named(name string) Logger {
+
+	if namespace, ok := logger.context.values[LogCtxNamespace]; ok && namespace != "" {
+		name = namespace + "." + name
+	}
+	return logger.withLogContext(logger.context.withValues(map[string]string{LogCtxNamespace: name}))
+}
+
+// withLogContext returns a copy of current logger using passed log context.
+func (logger *LogHandler) withLogContext(logContext LogContext) *LogHandler {
+	return &LogHandler{
+		logLevel:  logger.logLevel,
+		context:   logContext,
+		formatter: logger.formatter,
+		shipper:   logger.shipper,
+		hooks:     logger.hooks,
+		clock:     logger.clock,
+	}
+}
+
 // Statusf format given log message for log level Status.
 func (logger *LogHandler) Statusf(message string, v ...interface{}) {
 	logger.logf(Status, message, v...)
@@ -82,7 +164,69 @@ func (logger *LogHandler) Log(logLevel LogLevel, v ...interface{}) {
 	logger.log(logLevel, v...)
 }
 
+// Statusw will create a log message with given fields for log level Status.
+func (logger *LogHandler) Statusw(message string, keysAndValues ...interface{}) {
+	logger.logw(Status, message, keysAndValues)
+}
+
+// Errorw will create a log message with given fields for log level Error.
+func (logger *LogHandler) Errorw(message string, keysAndValues ...interface{}) {
+	logger.logw(Error, message, keysAndValues)
+}
+
+// Infow will create a log message with given fields for log level Info.
+func (logger *LogHandler) Infow(message string, keysAndValues ...interface{}) {
+	logger.logw(Info, message, keysAndValues)
+}
+
+// Debugw will create a log message with given fields for log level Debug.
+func (logger *LogHandler) Debugw(message string, keysAndValues ...interface{}) {
+	logger.logw(Debug, message, keysAndValues)
+}
+
+// Logw will create a log message with given fields for passed log level.
+func (logger *LogHandler) Logw(logLevel LogLevel, message string, keysAndValues ...interface{}) {
+	logger.logw(logLevel, message, keysAndValues)
+}
+
+// Metric creates a record for passed metric. Metrics are logged with log level Status,
+// so they're shipped unless logging is disabled.
+func (logger *LogHandler) Metric(name string, value float64, unit MetricUnit, dimensions ...Field) {
+	logger.metrics([]Metric{{Name: name, Value: value, Unit: unit}}, dimensions)
+}
+
+// Metrics creates a record for all passed metrics.
+func (logger *LogHandler) Metrics(metrics []Metric, dimensions ...Field) {
+	logger.metrics(metrics, dimensions)
+}
+
+// metrics creates a record with passed metrics. Dimensions and metric values are added as fields
+// as well, so formatters without metric support log them like other values.
+func (logger *LogHandler) metrics(metrics []Metric, dimensions []Field) {
+
+	if logger.logLevel < Status || len(metrics) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(metrics))
+	fields := make([]Field, 0, len(dimensions)+len(metrics))
+	fields = append(fields, dimensions...)
+	for _, metric := range metrics {
+		names = append(names, metric.Name)
+		fields = append(fields, Float64(metric.Name, metric.Value))
+	}
+
+	record := logger.newRecord(Status, strings.Join(names, ", "), logger.context.AppendFields(fields...))
+	record.Metrics = metrics
+	for _, dimension := range dimensions {
+		record.Dimensions = append(record.Dimensions, dimension.Key)
+	}
+	logger.ship(record)
+}
+
 // Flush will force it's log shipper to deliver all remaining log messages.
 func (logger *LogHandler) Flush() {
-	logger.shipper.flush()
+	if err := logger.shipper.Flush(); err != nil {
+		log.Println(err)
+	}
 }
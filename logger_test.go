@@ -2,6 +2,9 @@ package log
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -100,6 +103,84 @@ func (suite *LoggerTestSuite) TestLoggingWithContext() {
 	logger.Flush()
 }
 
+func (suite *LoggerTestSuite) TestLoggingWithFields() {
+
+	shipper := newTestShipper().(*testShipper)
+	logger := NewLogger(Info, nil, shipper)
+
+	logger.Infow("This is a test.", "count", 3, Bool("ok", true))
+	suite.assertLogMessage(1, "Info: This is a test., Context: count:3,ok:true", shipper)
+
+	logger.Statusw("This is a test.", "count", 3)
+	suite.assertLogMessage(2, "Status: This is a test., Context: count:3", shipper)
+
+	logger.Errorw("This is a test.", "count", 3)
+	suite.assertLogMessage(3, "Error: This is a test., Context: count:3", shipper)
+
+	logger.Debugw("This is a test.", "count", 3)
+	suite.Len(shipper.messages, 3)
+
+	logger.Logw(Info, "This is a test.")
+	suite.assertLogMessage(4, "Info: This is a test., Context: ", shipper)
+
+	logger = WithFields(logger, String("key", "val"))
+	logger.Infow("This is a test.", "count", 3)
+	suite.assertLogMessage(5, "Info: This is a test., Context: count:3,key:val", shipper)
+}
+
+func (suite *LoggerTestSuite) TestChildLoggers() {
+
+	shipper := newTestShipper().(*testShipper)
+	logger := NewLogger(Info, nil, shipper)
+
+	requestLogger := logger.With(String("requestid", "req-1"))
+	componentLogger := requestLogger.Named("billing").Named("invoice")
+
+	logger.Info("This is a test.")
+	suite.assertLogMessage(1, "Info: This is a test., Context: ", shipper)
+
+	requestLogger.Info("This is a test.")
+	suite.assertLogMessage(2, "Info: This is a test., Context: requestid:req-1", shipper)
+
+	componentLogger.Info("This is a test.")
+	suite.assertLogMessage(3, "Info: This is a test., Context: namespace:billing.invoice,requestid:req-1", shipper)
+
+	suite.Equal(logger.(*LogHandler).shipper, componentLogger.(*LogHandler).shipper)
+	suite.Equal(logger.(*LogHandler).formatter, componentLogger.(*LogHandler).formatter)
+}
+
+func (suite *LoggerTestSuite) TestLoggingWithCustomFormatter() {
+
+	shipper := newTestShipper().(*testShipper)
+	logger := NewLogger(Info, &customFormatterForTest{}, shipper).With(Int("count", 3))
+	logger = AppendContextValues(logger, map[string]string{"key": "val"})
+
+	logger.Info("This is a test.")
+	suite.Len(shipper.messages, 1)
+	suite.Equal("Info|This is a test.|key=val|count=3", shipper.messages[0])
+
+	logger.Info("")
+	suite.Len(shipper.messages, 1)
+}
+
+// customFormatterForTest is a formatter which only uses exported values of a record.
+type customFormatterForTest struct{}
+
+func (formatter *customFormatterForTest) Format(record Record) ([]byte, error) {
+
+	if record.Message == "" {
+		return nil, errors.New("empty message")
+	}
+	parts := []string{record.Level.String(), record.Message}
+	for key, value := range record.Context.Values() {
+		parts = append(parts, key+"="+value)
+	}
+	for _, field := range record.Context.Fields() {
+		parts = append(parts, fmt.Sprintf("%s=%v", field.Key, field.Value()))
+	}
+	return []byte(strings.Join(parts, "|")), nil
+}
+
 func (suite *LoggerTestSuite) assertLogMessage(expectedNumberOfLogMessages int, expectedMessage string, in *testShipper) {
 	suite.Len(in.messages, expectedNumberOfLogMessages)
 	suite.Equal(expectedMessage, in.messages[expectedNumberOfLogMessages-1])
@@ -29,9 +29,12 @@ func (logLevel LogLevel) String() string {
 }
 
 // SyslogLevel returns corresponding syslog(3) log level.
+// Status is mapped to LOG_NOTICE, because it's used for normal but significant events and for metrics.
 func (logLevel LogLevel) SyslogLevel() int {
 
 	switch logLevel {
+	case Status:
+		return 5 // LOG_NOTICE
 	case Error:
 		return 3 // LOG_ERR
 	case Info:
@@ -43,6 +46,25 @@ func (logLevel LogLevel) SyslogLevel() int {
 	}
 }
 
+// OtelSeverityNumber returns the OpenTelemetry severity number for a log level.
+// Status is mapped to INFO2, because it's used for normal but significant events and for metrics,
+// the same way as Cloud Logging and Datadog formatters map it to notice.
+func (logLevel LogLevel) OtelSeverityNumber() int {
+
+	switch logLevel {
+	case Status:
+		return 10 // INFO2
+	case Error:
+		return 17 // ERROR
+	case Info:
+		return 9 // INFO
+	case Debug:
+		return 5 // DEBUG
+	default:
+		return 0 // UNSPECIFIED
+	}
+}
+
 // LogLevelByName will try to convert passed name of a log level
 // into a log level.
 // If there's no suitable log level for a given name, log level None is returned, which disables logging.
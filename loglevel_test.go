@@ -50,10 +50,19 @@ func (suite *LogLevelTestSuite) TestLogLevelFromConfig() {
 	os.Unsetenv(ENV_LOGLEVEL)
 }
 
+func (suite *LogLevelTestSuite) TestOtelSeverityNumber() {
+
+	suite.Equal(0, None.OtelSeverityNumber())
+	suite.Equal(10, Status.OtelSeverityNumber())
+	suite.Equal(17, Error.OtelSeverityNumber())
+	suite.Equal(9, Info.OtelSeverityNumber())
+	suite.Equal(5, Debug.OtelSeverityNumber())
+}
+
 func (suite *LogLevelTestSuite) TestSyslogLevel() {
 
 	suite.Equal(0, None.SyslogLevel())
-	suite.Equal(0, Status.SyslogLevel())
+	suite.Equal(5, Status.SyslogLevel())
 	suite.Equal(3, Error.SyslogLevel())
 	suite.Equal(6, Info.SyslogLevel())
 	suite.Equal(7, Debug.SyslogLevel())
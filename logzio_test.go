@@ -1,6 +1,7 @@
 package log
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -30,6 +31,7 @@ func (suite *LogzioShipperTestSuite) TestCreateShipperFromConfig() {
 
 	logzioShipper, _ := shipper.(*LogzioShipper)
 	suite.Equal("https://example.com/", logzioShipper.logzioUrl)
+	suite.Equal(LOGZIO_TOKEN_KEY, logzioShipper.tokenKey)
 	suite.Equal(12, logzioShipper.batchSize)
 	suite.True(cap(logzioShipper.shipmentStack) == 3)
 	suite.True(cap(logzioShipper.messageStack) == 123)
@@ -40,53 +42,53 @@ func (suite *LogzioShipperTestSuite) TestCreateShipperFromConfig() {
 func (suite *LogzioShipperTestSuite) TestLogWithoutShipment() {
 
 	shipper := suite.shipperForTest()
-	logMessage := "Debug: Log Message"
+	logMessage := Record{Level: Debug, Message: "Log Message", Payload: []byte("Debug: Log Message")}
 
-	shipper.send(logMessage)
+	shipper.Ship(context.Background(), []Record{logMessage})
 	suite.Len(shipper.messageStack, 1)
 }
 
 func (suite *LogzioShipperTestSuite) TestLogWithShipment() {
 
 	shipper := suite.shipperForTest()
-	logMessage := "Debug: Log Message"
+	logMessage := Record{Level: Debug, Message: "Log Message", Payload: []byte("Debug: Log Message")}
 	for i := 1; i <= shipper.batchSize; i++ {
-		shipper.send(logMessage)
+		shipper.Ship(context.Background(), []Record{logMessage})
 	}
 	suite.Len(shipper.messageStack, shipper.batchSize)
 
 	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 200}
-	shipper.send(logMessage)
+	shipper.Ship(context.Background(), []Record{logMessage})
 
 	time.Sleep(1 * time.Second)
 	suite.Len(shipper.messageStack, 1)
-	suite.Len(shipper.httpClient.(*testClient).requests, 1)
+	suite.Len(shipper.httpClient.(*testClient).recordedRequests(), 1)
 }
 
 func (suite *LogzioShipperTestSuite) TestObtainShipmentTimeout() {
 
 	shipper := suite.shipperForTest()
 	shipper.shipmentStack = make(chan bool, 1)
-	logMessage := "Debug: Log Message"
+	logMessage := Record{Level: Debug, Message: "Log Message", Payload: []byte("Debug: Log Message")}
 	for i := 1; i <= shipper.batchSize; i++ {
-		shipper.send(logMessage)
+		shipper.Ship(context.Background(), []Record{logMessage})
 	}
 	suite.Len(shipper.messageStack, shipper.batchSize)
 
 	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 200}
-	shipper.send(logMessage)
+	shipper.Ship(context.Background(), []Record{logMessage})
 
 	time.Sleep(2 * time.Second)
 	suite.Len(shipper.messageStack, 4)
-	suite.Len(shipper.httpClient.(*testClient).requests, 0)
+	suite.Len(shipper.httpClient.(*testClient).recordedRequests(), 0)
 }
 
 func (suite *LogzioShipperTestSuite) TestReadMessageTimeout() {
 
 	shipper := suite.shipperForTest()
-	logMessage := "Debug: Log Message"
+	logMessage := Record{Level: Debug, Message: "Log Message", Payload: []byte("Debug: Log Message")}
 	for i := 1; i <= shipper.batchSize; i++ {
-		shipper.send(logMessage)
+		shipper.Ship(context.Background(), []Record{logMessage})
 	}
 	suite.Len(shipper.messageStack, shipper.batchSize)
 
@@ -94,7 +96,7 @@ func (suite *LogzioShipperTestSuite) TestReadMessageTimeout() {
 	shipper.obtainShipmentTimeout = 5 * time.Second
 	<-shipper.shipmentStack
 	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 200}
-	go shipper.send(logMessage)
+	go shipper.Ship(context.Background(), []Record{logMessage})
 	time.Sleep(1 * time.Second)
 
 	// read two messages from internal channel to have less messages than batch size
@@ -106,58 +108,58 @@ func (suite *LogzioShipperTestSuite) TestReadMessageTimeout() {
 
 	time.Sleep(2 * time.Second)
 	suite.Len(shipper.messageStack, 0)
-	suite.Len(shipper.httpClient.(*testClient).requests, 1)
+	suite.Len(shipper.httpClient.(*testClient).recordedRequests(), 1)
 }
 
 func (suite *LogzioShipperTestSuite) TestShipmentWithFailedRequest() {
 
 	shipper := suite.shipperForTest()
-	logMessage := "Debug: Log Message"
+	logMessage := Record{Level: Debug, Message: "Log Message", Payload: []byte("Debug: Log Message")}
 	for i := 1; i <= shipper.batchSize; i++ {
-		shipper.send(logMessage)
+		shipper.Ship(context.Background(), []Record{logMessage})
 	}
 	suite.Len(shipper.messageStack, shipper.batchSize)
 
 	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 400, Body: ioutil.NopCloser(strings.NewReader("Shipment Error!"))}
-	shipper.send(logMessage)
+	shipper.Ship(context.Background(), []Record{logMessage})
 
 	time.Sleep(1 * time.Second)
 	suite.Len(shipper.messageStack, 1)
-	suite.Len(shipper.httpClient.(*testClient).requests, 1)
+	suite.Len(shipper.httpClient.(*testClient).recordedRequests(), 1)
 }
 
 func (suite *LogzioShipperTestSuite) TestShipmentWithRequestError() {
 
 	shipper := suite.shipperForTest()
-	logMessage := "Debug: Log Message"
+	logMessage := Record{Level: Debug, Message: "Log Message", Payload: []byte("Debug: Log Message")}
 	for i := 1; i <= shipper.batchSize; i++ {
-		shipper.send(logMessage)
+		shipper.Ship(context.Background(), []Record{logMessage})
 	}
 	suite.Len(shipper.messageStack, shipper.batchSize)
 
 	shipper.httpClient.(*testClient).err = errors.New("Shipment Error!")
-	shipper.send(logMessage)
+	shipper.Ship(context.Background(), []Record{logMessage})
 
 	time.Sleep(1 * time.Second)
 	suite.Len(shipper.messageStack, 1)
-	suite.Len(shipper.httpClient.(*testClient).requests, 1)
+	suite.Len(shipper.httpClient.(*testClient).recordedRequests(), 1)
 }
 
 func (suite *LogzioShipperTestSuite) TestFlusgMessages() {
 
 	shipper := suite.shipperForTest()
-	logMessage := "Debug: Log Message"
+	logMessage := Record{Level: Debug, Message: "Log Message", Payload: []byte("Debug: Log Message")}
 	for i := 1; i <= shipper.batchSize; i++ {
-		shipper.send(logMessage)
+		shipper.Ship(context.Background(), []Record{logMessage})
 	}
 	suite.Len(shipper.messageStack, shipper.batchSize)
 
 	shipper.httpClient.(*testClient).response = &http.Response{StatusCode: 200}
-	shipper.flush()
+	shipper.Flush()
 
 	time.Sleep(1 * time.Second)
 	suite.Len(shipper.messageStack, 0)
-	suite.Len(shipper.httpClient.(*testClient).requests, 1)
+	suite.Len(shipper.httpClient.(*testClient).recordedRequests(), 1)
 }
 
 func (suite *LogzioShipperTestSuite) TestGetLogzIoUrl() {
@@ -178,23 +180,28 @@ func (suite *LogzioShipperTestSuite) TestLogzioIntegration() {
 	shipper := newLogzioShipper(conf, secrets.NewSecretsManager())
 
 	formatter := newLogzioJsonFormatter()
-	logMessage := formatter.format(Debug, newLogContext(make(map[string]string)), "go-log test")
+	logMessage := Record{Level: Debug, Context: newEmptyLogContext(), Message: "go-log test"}
+	logMessage.Payload, _ = formatter.Format(logMessage)
 	for i := 1; i <= shipper.(*LogzioShipper).batchSize; i++ {
-		shipper.send(logMessage)
+		shipper.Ship(context.Background(), []Record{logMessage})
 	}
-	shipper.flush()
+	shipper.Flush()
 }
 
 func (suite *LogzioShipperTestSuite) shipperForTest() *LogzioShipper {
 	shipper := &LogzioShipper{
-		logzioUrl:             "https://localhost:8071/",
+		logzioUrl:      "https://localhost:8071/",
+		tokenKey:       LOGZIO_TOKEN_KEY,
+		httpClient:     newHttpTestClient(nil, nil),
+		secretsManager: suite.secretsManagerForTest(),
+	}
+	shipper.batchShipper = batchShipper{
 		batchSize:             3,
 		shipmentStack:         make(chan bool, 1),
-		messageStack:          make(chan string, 10),
+		messageStack:          make(chan Record, 10),
 		obtainShipmentTimeout: 500 * time.Millisecond,
 		messageReadTimeout:    500 * time.Millisecond,
-		httpClient:            newHttpTestClient(nil, nil),
-		secretsManager:        suite.secretsManagerForTest(),
+		send:                  shipper.sendBatch,
 	}
 	shipper.initShipmentStack()
 	return shipper
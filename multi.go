@@ -0,0 +1,121 @@
+package log
+
+import (
+	"context"
+	"errors"
+
+	config "github.com/tommzn/go-config"
+	secrets "github.com/tommzn/go-secrets"
+)
+
+// NewMultiShipper returns a shipper which dispatches each record to all passed outputs.
+func NewMultiShipper(outputs ...LogOutput) LogShipper {
+	return &MultiShipper{outputs: outputs}
+}
+
+// newMultiShipperFromConfig creates an output for each passed output config.
+// Each output config can define a shipper, a formatter and a log level, e.g.
+//
+//	log:
+//	  outputs:
+//	    - shipper: logzio
+//	      loglevel: error
+//	    - shipper: stdout
+//	      formatter: default
+//	      loglevel: debug
+//
+// Outputs without a log level will use the log level defined at log.loglevel.
+func newMultiShipperFromConfig(conf config.Config, outputConfigs []map[string]string, secretsManager secrets.SecretsManager) LogShipper {
+
+	outputs := []LogOutput{}
+	for _, outputConfig := range outputConfigs {
+
+		formatter, shipper := formatterAndShipperByName(conf, outputConfig, secretsManager)
+		logLevel := LogLevelFromConfig(conf)
+		if logLevelName, ok := outputConfig["loglevel"]; ok {
+			logLevel = LogLevelByName(logLevelName)
+		}
+		outputs = append(outputs, LogOutput{LogLevel: logLevel, Formatter: formatter, Shipper: shipper})
+	}
+	return NewMultiShipper(outputs...)
+}
+
+// Ship passes all records to each output which accepts their log level.
+// Records are formatted by the formatter of an output before they're passed to it's shipper.
+// Errors of all outputs are returned together.
+func (shipper *MultiShipper) Ship(ctx context.Context, records []Record) error {
+
+	var errs []error
+	for _, output := range shipper.outputs {
+
+		outputRecords, err := output.accept(records)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if len(outputRecords) == 0 {
+			continue
+		}
+		if err := output.Shipper.Ship(ctx, outputRecords); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Flush calls flush for all outputs.
+func (shipper *MultiShipper) Flush() error {
+
+	var errs []error
+	for _, output := range shipper.outputs {
+		if err := output.Shipper.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// maxLogLevel returns the most verbose log level of all outputs.
+func (shipper *MultiShipper) maxLogLevel() LogLevel {
+
+	logLevel := None
+	for _, output := range shipper.outputs {
+		if output.LogLevel > logLevel {
+			logLevel = output.LogLevel
+		}
+	}
+	return logLevel
+}
+
+// formatsRecords returns true if all outputs have a formatter, so records don't have to be formatted before.
+func (shipper *MultiShipper) formatsRecords() bool {
+	for _, output := range shipper.outputs {
+		if output.Formatter == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// accept returns all passed records an output accepts, formatted by it's formatter.
+// Records which can not be formatted are skipped.
+func (output LogOutput) accept(records []Record) ([]Record, error) {
+
+	var errs []error
+	outputRecords := []Record{}
+	for _, record := range records {
+
+		if output.LogLevel < record.Level {
+			continue
+		}
+		if output.Formatter != nil {
+			payload, err := output.Formatter.Format(record)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			record.Payload = payload
+		}
+		outputRecords = append(outputRecords, record)
+	}
+	return outputRecords, errors.Join(errs...)
+}
@@ -0,0 +1,121 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MultiShipperTestSuite struct {
+	suite.Suite
+}
+
+func TestMultiShipperTestSuite(t *testing.T) {
+	suite.Run(t, new(MultiShipperTestSuite))
+}
+
+func (suite *MultiShipperTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/outputs.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.Equal(Debug, logger.(*LogHandler).logLevel)
+
+	multiShipper, ok := logger.(*LogHandler).shipper.(*MultiShipper)
+	suite.True(ok)
+	suite.Len(multiShipper.outputs, 2)
+	suite.Equal(Error, multiShipper.outputs[0].LogLevel)
+	suite.IsType(&LogzioJsonFormatter{}, multiShipper.outputs[0].Formatter)
+	suite.IsType(&LogzioShipper{}, multiShipper.outputs[0].Shipper)
+	suite.Equal(Debug, multiShipper.outputs[1].LogLevel)
+	suite.IsType(&LogzioJsonFormatter{}, multiShipper.outputs[1].Formatter)
+	suite.IsType(&StdoutShipper{}, multiShipper.outputs[1].Shipper)
+}
+
+func (suite *MultiShipperTestSuite) TestDispatchByLogLevel() {
+
+	errorShipper := newTestShipper().(*testShipper)
+	debugShipper := newTestShipper().(*testShipper)
+	logger := NewLogger(Debug, nil, NewMultiShipper(
+		LogOutput{LogLevel: Error, Formatter: &customFormatterForTest{}, Shipper: errorShipper},
+		LogOutput{LogLevel: Debug, Shipper: debugShipper},
+	))
+
+	logger.Error("This is a test.")
+	logger.Debug("This is a test.")
+	logger.Status("This is a test.")
+
+	suite.Equal([]string{"Error|This is a test.", "Status|This is a test."}, errorShipper.messages)
+	suite.Equal([]string{
+		"Error: This is a test., Context: ",
+		"Debug: This is a test., Context: ",
+		"Status: This is a test., Context: ",
+	}, debugShipper.messages)
+
+	logger.Flush()
+}
+
+func (suite *MultiShipperTestSuite) TestFormatOncePerOutput() {
+
+	topLevelHook := &testHook{}
+	outputHook := &testHook{}
+	testShipper := newTestShipper().(*testShipper)
+	logger := NewLogger(Debug, &recordFormatterForTest{hook: topLevelHook}, NewMultiShipper(
+		LogOutput{LogLevel: Debug, Formatter: &recordFormatterForTest{hook: outputHook}, Shipper: testShipper},
+	))
+	logger.Info("This is a test.")
+	suite.Len(topLevelHook.records, 0)
+	suite.Len(outputHook.records, 1)
+
+	router := NewRouterShipper(map[string]LogOutput{
+		"default": {LogLevel: Debug, Formatter: &recordFormatterForTest{hook: outputHook}, Shipper: testShipper},
+	}, nil, "default")
+	NewLogger(Debug, &recordFormatterForTest{hook: topLevelHook}, router).Info("This is a test.")
+	suite.Len(topLevelHook.records, 0)
+	suite.Len(outputHook.records, 2)
+
+	// An output without formatter uses the payload created by the formatter of the logger.
+	logger = NewLogger(Debug, &recordFormatterForTest{hook: topLevelHook}, NewMultiShipper(
+		LogOutput{LogLevel: Debug, Formatter: &recordFormatterForTest{hook: outputHook}, Shipper: testShipper},
+		LogOutput{LogLevel: Debug, Shipper: testShipper},
+	))
+	logger.Info("This is a test.")
+	suite.Len(topLevelHook.records, 1)
+	suite.Len(outputHook.records, 3)
+}
+
+func (suite *MultiShipperTestSuite) TestErrorsOfOutputs() {
+
+	testShipper := newTestShipper().(*testShipper)
+	shipper := NewMultiShipper(
+		LogOutput{LogLevel: Debug, Formatter: &customFormatterForTest{}, Shipper: testShipper},
+		LogOutput{LogLevel: Debug, Shipper: &failingShipperForTest{err: errors.New("shipment failed")}},
+	)
+
+	records := []Record{
+		{Level: Info, Message: "", Payload: []byte("Info: ")},
+		{Level: Info, Message: "This is a test.", Payload: []byte("Info: This is a test.")},
+	}
+	err := shipper.Ship(context.Background(), records)
+	suite.ErrorContains(err, "empty message")
+	suite.ErrorContains(err, "shipment failed")
+	suite.Equal([]string{"Info|This is a test."}, testShipper.messages)
+
+	suite.EqualError(shipper.Flush(), "flush failed")
+}
+
+// failingShipperForTest is a shipper which returns an error for each call.
+type failingShipperForTest struct {
+	err     error
+	records []Record
+}
+
+func (shipper *failingShipperForTest) Ship(ctx context.Context, records []Record) error {
+	shipper.records = append(shipper.records, records...)
+	return shipper.err
+}
+
+func (shipper *failingShipperForTest) Flush() error {
+	return errors.New("flush failed")
+}
@@ -0,0 +1,210 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	config "github.com/tommzn/go-config"
+)
+
+// ENV_OTEL_SERVICE_NAME is the environment variable used by OpenTelemetry SDKs for the name of a service.
+const ENV_OTEL_SERVICE_NAME = "OTEL_SERVICE_NAME"
+
+// OTLP_SCOPE_NAME is used as name of the instrumentation scope of all log records.
+const OTLP_SCOPE_NAME = "github.com/tommzn/go-log"
+
+// otlpResourceAttributes maps context keys, e.g. from DefaultContextForNodes or DefaultContextForK8s,
+// to OpenTelemetry resource attributes.
+var otlpResourceAttributes = map[string]string{
+	LogCtxHostname: "host.name",
+	LogCtxIp:       "host.ip",
+	LogCtxK8sNode:  "k8s.node.name",
+	LogCtxK8sPod:   "k8s.pod.name",
+}
+
+// otlpLogsData is the top level message of OTLP/JSON logs.
+type otlpLogsData struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+// otlpResourceLogs is a collection of logs from a resource.
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+// otlpResource describes the entity producing logs.
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+// otlpScopeLogs is a collection of logs produced by an instrumentation scope.
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+// otlpScope is the instrumentation scope of logs.
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+// otlpLogRecord is a single log record. Timestamps are 64 bit integers,
+// which are encoded as strings in OTLP/JSON.
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano,omitempty"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceId              string         `json:"traceId,omitempty"`
+	SpanId               string         `json:"spanId,omitempty"`
+}
+
+// otlpKeyValue is an attribute of a resource or a log record.
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpAnyValue contains exactly one value of a supported type.
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// newOtlpJsonFormatter returns a new OtlpJsonFormatter. Service name is read from environment
+// variable OTEL_SERVICE_NAME and can be set by config: log.formatter.service
+func newOtlpJsonFormatter(conf config.Config) LogFormatter {
+
+	serviceName := os.Getenv(ENV_OTEL_SERVICE_NAME)
+	if conf != nil {
+		serviceName = *conf.Get("log.formatter.service", config.AsStringPtr(serviceName))
+	}
+	return &OtlpJsonFormatter{serviceName: serviceName}
+}
+
+// withClock returns a copy of this formatter which uses passed clock for the observed time of records.
+func (formatter *OtlpJsonFormatter) withClock(clock Clock) LogFormatter {
+	return &OtlpJsonFormatter{serviceName: formatter.serviceName, clock: clock}
+}
+
+// Format creates an OTLP/JSON document with a single log record. Host and kubernetes values
+// of the log context are added as resource attributes, trace and span id are used for correlation
+// and all other context values and fields are added as log record attributes.
+func (formatter *OtlpJsonFormatter) Format(record Record) ([]byte, error) {
+
+	resourceAttributes := make(map[string]otlpAnyValue)
+	attributes := make(map[string]otlpAnyValue)
+	for key, value := range record.Context.values {
+		attributes[key] = otlpStringValue(value)
+	}
+	for _, field := range record.Context.fields {
+		attributes[field.Key] = otlpValueFromField(field)
+	}
+	for key, attributeName := range otlpResourceAttributes {
+		if value, ok := attributes[key]; ok {
+			resourceAttributes[attributeName] = value
+			delete(attributes, key)
+		}
+	}
+	if formatter.serviceName != "" {
+		resourceAttributes["service.name"] = otlpStringValue(formatter.serviceName)
+	}
+	delete(attributes, LogCtxTraceId)
+	delete(attributes, LogCtxSpanId)
+	if record.Error != nil {
+		attributes["exception.message"] = otlpStringValue(record.Error.Error())
+	}
+	if record.Caller.IsDefined() {
+		attributes["code.function"] = otlpStringValue(record.Caller.Function)
+		attributes["code.filepath"] = otlpStringValue(record.Caller.File)
+		attributes["code.lineno"] = otlpIntValue(int64(record.Caller.Line))
+	}
+
+	logRecord := otlpLogRecord{
+		ObservedTimeUnixNano: strconv.FormatInt(nowFrom(formatter.clock).UnixNano(), 10),
+		SeverityNumber:       record.Level.OtelSeverityNumber(),
+		SeverityText:         strings.ToUpper(record.Level.String()),
+		Body:                 otlpStringValue(record.Message),
+		Attributes:           otlpKeyValues(attributes),
+	}
+	if !record.Time.IsZero() {
+		logRecord.TimeUnixNano = strconv.FormatInt(record.Time.UnixNano(), 10)
+	}
+	if traceId, ok := record.Context.lookup(LogCtxTraceId); ok {
+		logRecord.TraceId = traceId
+	}
+	if spanId, ok := record.Context.lookup(LogCtxSpanId); ok {
+		logRecord.SpanId = spanId
+	}
+
+	logsData := otlpLogsData{
+		ResourceLogs: []otlpResourceLogs{
+			{
+				Resource: otlpResource{Attributes: otlpKeyValues(resourceAttributes)},
+				ScopeLogs: []otlpScopeLogs{
+					{
+						Scope:      otlpScope{Name: OTLP_SCOPE_NAME},
+						LogRecords: []otlpLogRecord{logRecord},
+					},
+				},
+			},
+		},
+	}
+	return json.Marshal(logsData)
+}
+
+// otlpKeyValues converts passed attributes into a list, sorted by key.
+func otlpKeyValues(attributes map[string]otlpAnyValue) []otlpKeyValue {
+
+	keyValues := make([]otlpKeyValue, 0, len(attributes))
+	for key, value := range attributes {
+		keyValues = append(keyValues, otlpKeyValue{Key: key, Value: value})
+	}
+	sort.Slice(keyValues, func(i, j int) bool {
+		return keyValues[i].Key < keyValues[j].Key
+	})
+	return keyValues
+}
+
+// otlpValueFromField converts the value of passed field into an OTLP value.
+// Durations are converted to nanoseconds and values of other types to strings.
+func otlpValueFromField(field Field) otlpAnyValue {
+
+	switch field.Type {
+	case IntType:
+		return otlpIntValue(field.value.(int64))
+	case FloatType:
+		value := field.value.(float64)
+		return otlpAnyValue{DoubleValue: &value}
+	case BoolType:
+		value := field.value.(bool)
+		return otlpAnyValue{BoolValue: &value}
+	case DurationType:
+		return otlpIntValue(int64(field.value.(time.Duration)))
+	case AnyType:
+		return otlpStringValue(fmt.Sprint(field.value))
+	default:
+		return otlpStringValue(field.text())
+	}
+}
+
+// otlpStringValue returns an OTLP value for passed string.
+func otlpStringValue(value string) otlpAnyValue {
+	return otlpAnyValue{StringValue: &value}
+}
+
+// otlpIntValue returns an OTLP value for passed integer, which is encoded as string in OTLP/JSON.
+func otlpIntValue(value int64) otlpAnyValue {
+	intValue := strconv.FormatInt(value, 10)
+	return otlpAnyValue{IntValue: &intValue}
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type OtlpJsonFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestOtlpJsonFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(OtlpJsonFormatterTestSuite))
+}
+
+func (suite *OtlpJsonFormatterTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/otlp.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&OtlpJsonFormatter{}, logger.(*LogHandler).formatter)
+	suite.Equal("billing-service", logger.(*LogHandler).formatter.(*OtlpJsonFormatter).serviceName)
+
+	os.Setenv(ENV_OTEL_SERVICE_NAME, "env-service")
+	suite.Equal("env-service", newOtlpJsonFormatter(nil).(*OtlpJsonFormatter).serviceName)
+	os.Unsetenv(ENV_OTEL_SERVICE_NAME)
+}
+
+func (suite *OtlpJsonFormatterTestSuite) TestFormat() {
+
+	formatter := &OtlpJsonFormatter{serviceName: "billing-service"}
+	logContext := newLogContext(map[string]string{
+		LogCtxHostname:  "host01",
+		LogCtxK8sPod:    "billing-7d9f",
+		LogCtxNamespace: "billing",
+		LogCtxTraceId:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		LogCtxSpanId:    "00f067aa0ba902b7",
+	}).AppendFields(Int("count", 3), Float64("ratio", 0.5), Bool("retry", true),
+		Duration("elapsed", time.Second), Err(errors.New("timeout")))
+	record := newRecord(Error, "Test Message", logContext)
+	record.Time = time.Date(2021, 5, 30, 12, 8, 47, 123456789, time.UTC)
+	record.Caller = Caller{Function: "main.run", File: "main.go", Line: 12}
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+
+	logsData := otlpLogsData{}
+	suite.Nil(json.Unmarshal(logMessage, &logsData))
+	suite.Len(logsData.ResourceLogs, 1)
+	resourceLogs := logsData.ResourceLogs[0]
+	suite.Equal(map[string]interface{}{
+		"host.name":    "host01",
+		"k8s.pod.name": "billing-7d9f",
+		"service.name": "billing-service",
+	}, otlpAttributesForTest(resourceLogs.Resource.Attributes))
+
+	suite.Len(resourceLogs.ScopeLogs, 1)
+	suite.Equal(OTLP_SCOPE_NAME, resourceLogs.ScopeLogs[0].Scope.Name)
+	suite.Len(resourceLogs.ScopeLogs[0].LogRecords, 1)
+	logRecord := resourceLogs.ScopeLogs[0].LogRecords[0]
+	suite.Equal("1622376527123456789", logRecord.TimeUnixNano)
+	suite.NotEmpty(logRecord.ObservedTimeUnixNano)
+	suite.Equal(17, logRecord.SeverityNumber)
+	suite.Equal("ERROR", logRecord.SeverityText)
+	suite.Equal("Test Message", *logRecord.Body.StringValue)
+	suite.Equal("4bf92f3577b34da6a3ce929d0e0e4736", logRecord.TraceId)
+	suite.Equal("00f067aa0ba902b7", logRecord.SpanId)
+	suite.Equal(map[string]interface{}{
+		"namespace":         "billing",
+		"count":             "3",
+		"ratio":             0.5,
+		"retry":             true,
+		"elapsed":           "1000000000",
+		"error":             "timeout",
+		"exception.message": "timeout",
+		"code.function":     "main.run",
+		"code.filepath":     "main.go",
+		"code.lineno":       "12",
+	}, otlpAttributesForTest(logRecord.Attributes))
+}
+
+func (suite *OtlpJsonFormatterTestSuite) TestFormatWithoutResource() {
+
+	formatter := &OtlpJsonFormatter{}
+	record := newRecord(Status, "Test Message", newEmptyLogContext())
+
+	logMessage, err := formatter.Format(record)
+	suite.Nil(err)
+	suite.Contains(string(logMessage), `"resource":{}`)
+	suite.Contains(string(logMessage), `"severityNumber":10,"severityText":"STATUS"`)
+	suite.NotContains(string(logMessage), `"attributes"`)
+	suite.NotContains(string(logMessage), `"traceId"`)
+}
+
+// otlpAttributesForTest converts passed attributes into a map of their values.
+func otlpAttributesForTest(keyValues []otlpKeyValue) map[string]interface{} {
+
+	attributes := make(map[string]interface{})
+	for _, keyValue := range keyValues {
+		switch {
+		case keyValue.Value.StringValue != nil:
+			attributes[keyValue.Key] = *keyValue.Value.StringValue
+		case keyValue.Value.IntValue != nil:
+			attributes[keyValue.Key] = *keyValue.Value.IntValue
+		case keyValue.Value.DoubleValue != nil:
+			attributes[keyValue.Key] = *keyValue.Value.DoubleValue
+		case keyValue.Value.BoolValue != nil:
+			attributes[keyValue.Key] = *keyValue.Value.BoolValue
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,69 @@
+package log
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// recordSequence is increased for each new record.
+var recordSequence atomic.Uint64
+
+// newRecord creates a record for passed values with current time and next sequence number.
+func newRecord(logLevel LogLevel, message string, logContext LogContext) Record {
+	return Record{
+		Time:      time.Now(),
+		Level:     logLevel,
+		Message:   message,
+		Context:   logContext,
+		Namespace: logContext.values[LogCtxNamespace],
+		Error:     firstError(logContext.fields),
+		Sequence:  recordSequence.Add(1),
+	}
+}
+
+// callerAt returns the caller after skipping passed number of stack frames.
+// Skip 0 is the function calling callerAt.
+func callerAt(skip int) Caller {
+
+	pc, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return Caller{}
+	}
+	caller := Caller{File: file, Line: line}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		caller.Function = fn.Name()
+	}
+	return caller
+}
+
+// callerFromPC returns the caller for a program counter as it's provided by log/slog.
+func callerFromPC(pc uintptr) Caller {
+
+	if pc == 0 {
+		return Caller{}
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return Caller{Function: frame.Function, File: frame.File, Line: frame.Line}
+}
+
+// firstError returns the error of the first error field in passed fields.
+func firstError(fields []Field) error {
+	for _, field := range fields {
+		if err, ok := field.value.(error); ok && field.Type == ErrorType {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsDefined returns true if a caller has a source file.
+func (caller Caller) IsDefined() bool {
+	return caller.File != ""
+}
+
+// String returns file and line number of a caller.
+func (caller Caller) String() string {
+	return fmt.Sprintf("%s:%d", caller.File, caller.Line)
+}
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"errors"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RecordTestSuite struct {
+	suite.Suite
+}
+
+func TestRecordTestSuite(t *testing.T) {
+	suite.Run(t, new(RecordTestSuite))
+}
+
+func (suite *RecordTestSuite) TestNewRecord() {
+
+	err := errors.New("failed")
+	logContext := newLogContext(map[string]string{LogCtxNamespace: "billing"}).AppendFields(Int("count", 1), Err(err))
+
+	record1 := newRecord(Error, "Test Message", logContext)
+	record2 := newRecord(Error, "Test Message", logContext)
+	suite.Equal(Error, record1.Level)
+	suite.Equal("Test Message", record1.Message)
+	suite.Equal("billing", record1.Namespace)
+	suite.Equal(err, record1.Error)
+	suite.WithinDuration(time.Now(), record1.Time, time.Second)
+	suite.Equal(record1.Sequence+1, record2.Sequence)
+	suite.False(record1.Caller.IsDefined())
+}
+
+func (suite *RecordTestSuite) TestCaller() {
+
+	caller, line := callerAt(0), currentLineForTest()
+	suite.True(caller.IsDefined())
+	suite.True(strings.HasSuffix(caller.Function, "TestCaller"))
+	suite.True(strings.HasSuffix(caller.String(), "record_test.go:"+strconv.Itoa(line)))
+
+	suite.False(callerFromPC(0).IsDefined())
+}
+
+func (suite *RecordTestSuite) TestRecordPassedToHooks() {
+
+	hook := &testHook{}
+	shipper := newTestShipper().(*testShipper)
+	logger := WithHooks(NewLogger(Info, nil, shipper), hook).Named("billing")
+
+	logger.Error("This is a test.")
+	logger.Infof("This is the %dst test.", 1)
+	logger.Infow("This is a test.", Err(errors.New("failed")))
+	logger.Debug("This is a test.")
+	suite.Len(hook.records, 3)
+	suite.Len(shipper.messages, 3)
+
+	for _, record := range hook.records {
+		suite.Equal("billing", record.Namespace)
+		suite.True(strings.HasSuffix(record.Caller.Function, "TestRecordPassedToHooks"))
+		suite.True(strings.Contains(record.Caller.File, "record_test.go"))
+	}
+	suite.Equal(hook.records[0].Sequence+1, hook.records[1].Sequence)
+	suite.Nil(hook.records[0].Error)
+	suite.EqualError(hook.records[2].Error, "failed")
+
+	hook.err = errors.New("hook error")
+	logger.Error("This is a test.")
+	suite.Len(hook.records, 4)
+	suite.Len(shipper.messages, 4)
+
+	slogLogger := NewLoggerFromSlogHandler(nil)
+	suite.Equal(slogLogger, WithHooks(slogLogger, hook))
+}
+
+func (suite *RecordTestSuite) TestRecordFromSlog() {
+
+	hook := &testHook{}
+	shipper := newTestShipper().(*testShipper)
+	logger := slog.New(NewSlogHandler(Info, &recordFormatterForTest{hook: hook}, shipper))
+
+	logger.Info("This is a test.")
+	suite.Len(hook.records, 1)
+	suite.True(strings.HasSuffix(hook.records[0].Caller.Function, "TestRecordFromSlog"))
+}
+
+// recordFormatterForTest passes all records to a hook.
+type recordFormatterForTest struct {
+	hook *testHook
+}
+
+func (formatter *recordFormatterForTest) Format(record Record) ([]byte, error) {
+	return []byte(record.Message), formatter.hook.Fire(record)
+}
+
+// currentLineForTest returns the line it's called from.
+func currentLineForTest() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
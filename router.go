@@ -0,0 +1,219 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	config "github.com/tommzn/go-config"
+	secrets "github.com/tommzn/go-secrets"
+)
+
+// ROUTER_DEFAULT_SINK is the name of the sink used for records without a matching route.
+// Can be set by config: log.defaultsink
+const ROUTER_DEFAULT_SINK = "default"
+
+// NewRouterShipper returns a shipper which forwards each record to the sink defined by the first
+// matching route. Records without a matching route are forwarded to passed default sink. They're
+// dropped if default sink is empty. Routes and a default sink with names of sinks which don't
+// exist are written to STDERR and Ship returns an error for records routed to them.
+func NewRouterShipper(sinks map[string]LogOutput, routes []LogRoute, defaultSink string) LogShipper {
+
+	for _, route := range routes {
+		if _, ok := sinks[route.Sink]; !ok {
+			log.Printf("Route to unknown sink: %s", route.Sink)
+		}
+	}
+	if _, ok := sinks[defaultSink]; !ok && defaultSink != "" {
+		log.Printf("Unknown default sink: %s", defaultSink)
+	}
+	return &RouterShipper{
+		sinks:       sinks,
+		routes:      routes,
+		defaultSink: defaultSink,
+	}
+}
+
+// newRouterShipperFromConfig creates a router with sinks and routes defined in config, e.g.
+//
+//	log:
+//	  sinks:
+//	    - name: default
+//	      shipper: stdout
+//	    - name: billing
+//	      shipper: logzio
+//	      tokenkey: LOGZIO_BILLING_TOKEN
+//	  routes:
+//	    - sink: billing
+//	      namespace: billing
+//	    - sink: default
+//	      loglevel: status,error
+//	      context.tenant: acme
+//	  defaultsink: default
+//
+// Sinks are configured the same way as outputs of a MultiShipper. A route can match
+// a comma separated list of log levels, a namespace and context values with "context." as key prefix.
+// Context keys are lower case, because config keys are case insensitive.
+func newRouterShipperFromConfig(conf config.Config, sinkConfigs []map[string]string, secretsManager secrets.SecretsManager) LogShipper {
+
+	sinks := make(map[string]LogOutput)
+	for _, sinkConfig := range sinkConfigs {
+
+		formatter, shipper := formatterAndShipperByName(conf, sinkConfig, secretsManager)
+		logLevel := LogLevelFromConfig(conf)
+		if logLevelName, ok := sinkConfig["loglevel"]; ok {
+			logLevel = LogLevelByName(logLevelName)
+		}
+		sinks[sinkConfig["name"]] = LogOutput{LogLevel: logLevel, Formatter: formatter, Shipper: shipper}
+	}
+
+	routes := []LogRoute{}
+	for _, routeConfig := range conf.GetAsSliceOfMaps("log.routes") {
+		routes = append(routes, logRouteFromConfig(routeConfig))
+	}
+
+	defaultSink := conf.Get("log.defaultsink", config.AsStringPtr(ROUTER_DEFAULT_SINK))
+	return NewRouterShipper(sinks, routes, *defaultSink)
+}
+
+// logRouteFromConfig creates a route from passed config values.
+func logRouteFromConfig(routeConfig map[string]string) LogRoute {
+
+	route := LogRoute{
+		Sink:      routeConfig["sink"],
+		Namespace: routeConfig["namespace"],
+		LogLevels: []LogLevel{},
+		Context:   make(map[string]string),
+	}
+	if logLevelNames, ok := routeConfig["loglevel"]; ok {
+		for _, logLevelName := range strings.Split(logLevelNames, ",") {
+			route.LogLevels = append(route.LogLevels, LogLevelByName(strings.TrimSpace(logLevelName)))
+		}
+	}
+	for key, value := range routeConfig {
+		if contextKey, ok := strings.CutPrefix(key, "context."); ok {
+			route.Context[contextKey] = value
+		}
+	}
+	return route
+}
+
+// Ship forwards passed records to the sinks of their routes. Records routed to an unknown
+// sink are not shipped and an error is returned for them. Errors of all sinks are returned together.
+func (shipper *RouterShipper) Ship(ctx context.Context, records []Record) error {
+
+	sinkNames := []string{}
+	sinkRecords := make(map[string][]Record)
+	for _, record := range records {
+		sinkName := shipper.sinkFor(record)
+		if _, ok := sinkRecords[sinkName]; !ok {
+			sinkNames = append(sinkNames, sinkName)
+		}
+		sinkRecords[sinkName] = append(sinkRecords[sinkName], record)
+	}
+
+	var errs []error
+	for _, sinkName := range sinkNames {
+
+		if sinkName == "" {
+			continue
+		}
+		sink, ok := shipper.sinks[sinkName]
+		if !ok {
+			errs = append(errs, fmt.Errorf("Unknown sink %s, %d records not shipped", sinkName, len(sinkRecords[sinkName])))
+			continue
+		}
+		outputRecords, err := sink.accept(sinkRecords[sinkName])
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if len(outputRecords) == 0 {
+			continue
+		}
+		if err := sink.Shipper.Ship(ctx, outputRecords); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Flush calls flush for all sinks.
+func (shipper *RouterShipper) Flush() error {
+
+	sinkNames := make([]string, 0, len(shipper.sinks))
+	for sinkName := range shipper.sinks {
+		sinkNames = append(sinkNames, sinkName)
+	}
+	sort.Strings(sinkNames)
+
+	var errs []error
+	for _, sinkName := range sinkNames {
+		if err := shipper.sinks[sinkName].Shipper.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sinkFor returns the sink of the first route matching passed record or the default sink.
+func (shipper *RouterShipper) sinkFor(record Record) string {
+	for _, route := range shipper.routes {
+		if route.matches(record) {
+			return route.Sink
+		}
+	}
+	return shipper.defaultSink
+}
+
+// maxLogLevel returns the most verbose log level of all sinks.
+func (shipper *RouterShipper) maxLogLevel() LogLevel {
+
+	logLevel := None
+	for _, sink := range shipper.sinks {
+		if sink.LogLevel > logLevel {
+			logLevel = sink.LogLevel
+		}
+	}
+	return logLevel
+}
+
+// formatsRecords returns true if all sinks have a formatter, so records don't have to be formatted before.
+func (shipper *RouterShipper) formatsRecords() bool {
+	for _, sink := range shipper.sinks {
+		if sink.Formatter == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns true if passed record fulfills all conditions of a route.
+func (route LogRoute) matches(record Record) bool {
+
+	if len(route.LogLevels) > 0 && !containsLogLevel(route.LogLevels, record.Level) {
+		return false
+	}
+	if route.Namespace != "" && record.Namespace != route.Namespace &&
+		!strings.HasPrefix(record.Namespace, route.Namespace+".") {
+		return false
+	}
+	for key, value := range route.Context {
+		if contextValue, ok := record.Context.lookup(key); !ok || contextValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// containsLogLevel returns true if passed log level is in given list.
+func containsLogLevel(logLevels []LogLevel, logLevel LogLevel) bool {
+	for _, level := range logLevels {
+		if level == logLevel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RouterShipperTestSuite struct {
+	suite.Suite
+}
+
+func TestRouterShipperTestSuite(t *testing.T) {
+	suite.Run(t, new(RouterShipperTestSuite))
+}
+
+func (suite *RouterShipperTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/router.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.Equal(Debug, logger.(*LogHandler).logLevel)
+
+	router, ok := logger.(*LogHandler).shipper.(*RouterShipper)
+	suite.True(ok)
+	suite.Len(router.sinks, 3)
+	suite.Equal("default", router.defaultSink)
+	suite.Equal(Error, router.sinks["billing"].LogLevel)
+	suite.Equal("https://billing.example.com/", router.sinks["billing"].Shipper.(*LogzioShipper).logzioUrl)
+	suite.Equal("LOGZIO_BILLING_TOKEN", router.sinks["billing"].Shipper.(*LogzioShipper).tokenKey)
+	suite.IsType(&LogzioJsonFormatter{}, router.sinks["audit"].Formatter)
+
+	suite.Len(router.routes, 2)
+	suite.Equal(LogRoute{Sink: "billing", Namespace: "billing", LogLevels: []LogLevel{}, Context: map[string]string{}}, router.routes[0])
+	suite.Equal(LogRoute{Sink: "audit", LogLevels: []LogLevel{Status}, Context: map[string]string{"tenant": "acme"}}, router.routes[1])
+}
+
+func (suite *RouterShipperTestSuite) TestRouting() {
+
+	defaultShipper := newTestShipper().(*testShipper)
+	billingShipper := newTestShipper().(*testShipper)
+	auditShipper := newTestShipper().(*testShipper)
+	router := NewRouterShipper(
+		map[string]LogOutput{
+			"default": {LogLevel: Info, Shipper: defaultShipper},
+			"billing": {LogLevel: Error, Shipper: billingShipper},
+			"audit":   {LogLevel: Debug, Formatter: &customFormatterForTest{}, Shipper: auditShipper},
+		},
+		[]LogRoute{
+			{Sink: "billing", Namespace: "billing"},
+			{Sink: "audit", LogLevels: []LogLevel{Status, Error}, Context: map[string]string{"tenant": "acme"}},
+		},
+		"default")
+	logger := NewLogger(Debug, nil, router)
+
+	logger.Named("billing").Error("This is a test.")
+	logger.Named("billing").Named("invoice").Error("This is a test.")
+	logger.Named("billing").Info("This is a test.")
+	logger.Named("billingx").Info("This is a test.")
+	logger.With(String("tenant", "acme")).Status("This is a test.")
+	logger.With(String("tenant", "other")).Status("This is a test.")
+	logger.With(String("tenant", "acme")).Info("This is a test.")
+	logger.Debug("This is a test.")
+
+	suite.Equal([]string{
+		"Error: This is a test., Context: namespace:billing",
+		"Error: This is a test., Context: namespace:billing.invoice",
+	}, billingShipper.messages)
+	suite.Equal([]string{"Status|This is a test.|tenant=acme"}, auditShipper.messages)
+	suite.Equal([]string{
+		"Info: This is a test., Context: namespace:billingx",
+		"Status: This is a test., Context: tenant:other",
+		"Info: This is a test., Context: tenant:acme",
+	}, defaultShipper.messages)
+
+	logger.Flush()
+}
+
+func (suite *RouterShipperTestSuite) TestRoutingWithoutDefaultSink() {
+
+	testShipper := newTestShipper().(*testShipper)
+	router := NewRouterShipper(
+		map[string]LogOutput{
+			"audit":  {LogLevel: Debug, Shipper: testShipper},
+			"broken": {LogLevel: Debug, Shipper: &failingShipperForTest{err: errors.New("shipment failed")}},
+		},
+		[]LogRoute{{Sink: "audit", LogLevels: []LogLevel{Status}}, {Sink: "broken", LogLevels: []LogLevel{Error}}},
+		"default")
+
+	records := []Record{
+		{Level: Status, Payload: []byte("Status")},
+		{Level: Info, Payload: []byte("Info")},
+		{Level: Error, Payload: []byte("Error")},
+	}
+	suite.EqualError(router.Ship(context.Background(), records), "Unknown sink default, 1 records not shipped\nshipment failed")
+	suite.Equal([]string{"Status"}, testShipper.messages)
+	suite.EqualError(router.Flush(), "flush failed")
+
+	router = NewRouterShipper(map[string]LogOutput{"audit": {LogLevel: Debug, Shipper: testShipper}},
+		[]LogRoute{{Sink: "audit", LogLevels: []LogLevel{Status}}}, "")
+	suite.Nil(router.Ship(context.Background(), records))
+	suite.Equal([]string{"Status", "Status"}, testShipper.messages)
+}
@@ -0,0 +1,417 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"runtime"
+	"strings"
+	"time"
+
+	config "github.com/tommzn/go-config"
+	secrets "github.com/tommzn/go-secrets"
+)
+
+// LevelStatus is a slog level which is mapped to log level Status.
+const LevelStatus = slog.LevelError + 4
+
+// NewSlogHandler returns a slog.Handler with passed log level, formatter and shipper.
+// If you omit formatter and shipper the DefaultFormatter and StdoutShipper will be used.
+func NewSlogHandler(logLevel LogLevel, formatter LogFormatter, shipper LogShipper) slog.Handler {
+
+	if formatter == nil {
+		formatter = newDefaultFormatter()
+	}
+	if shipper == nil {
+		shipper = newStdoutShipper()
+	}
+	return &SlogHandler{
+		logLevel:  logLevel,
+		context:   newEmptyLogContext(),
+		groups:    []string{},
+		formatter: formatter,
+		shipper:   shipper,
+	}
+}
+
+// NewSlogHandlerFromConfig returns a slog.Handler with log level, formatter and shipper
+// created from passed config, the same way NewLoggerFromConfig does.
+func NewSlogHandlerFromConfig(conf config.Config, secretsManager secrets.SecretsManager) slog.Handler {
+
+	formatter, shipper := formatterAndShipperFromConfig(conf, secretsManager)
+	return NewSlogHandler(logLevelForShipper(conf, shipper), formatter, shipper)
+}
+
+// LogLevelFromSlogLevel converts passed slog level to a log level.
+// Warnings are mapped to Info, because there's no corresponding log level.
+func LogLevelFromSlogLevel(level slog.Level) LogLevel {
+
+	switch {
+	case level >= LevelStatus:
+		return Status
+	case level >= slog.LevelError:
+		return Error
+	case level >= slog.LevelInfo:
+		return Info
+	default:
+		return Debug
+	}
+}
+
+// SlogLevel returns the slog level corresponding to a log level.
+func (logLevel LogLevel) SlogLevel() slog.Level {
+
+	switch logLevel {
+	case Status:
+		return LevelStatus
+	case Error:
+		return slog.LevelError
+	case Info:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// Enabled reports whether passed level is enabled by log level of this handler.
+func (handler *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return handler.logLevel >= LogLevelFromSlogLevel(level)
+}
+
+// Handle converts all attributes of passed record to fields, formats the record
+// and forwards it to the shipper. Values of a log context in passed context are added as well.
+// A zero time of passed record is kept, so the timestamp is omitted by formatters.
+func (handler *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+
+	logContext := handler.context
+	if ctx != nil {
+		ctxLogContext := getLogContext(ctx)
+		logContext = logContext.withValues(ctxLogContext.values).AppendFields(ctxLogContext.fields...)
+	}
+
+	fields := []Field{}
+	record.Attrs(func(attr slog.Attr) bool {
+		fields = appendAttr(fields, handler.groups, attr)
+		return true
+	})
+	logContext = logContext.AppendFields(fields...)
+
+	logRecord := newRecord(LogLevelFromSlogLevel(record.Level), record.Message, logContext)
+	logRecord.Time = record.Time
+	logRecord.Caller = callerFromPC(record.PC)
+	return formatAndShip(ctx, handler.formatter, handler.shipper, logRecord)
+}
+
+// WithAttrs returns a new handler with passed attributes added as fields.
+func (handler *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+
+	fields := []Field{}
+	for _, attr := range attrs {
+		fields = appendAttr(fields, handler.groups, attr)
+	}
+	newHandler := handler.clone()
+	newHandler.context = handler.context.AppendFields(fields...)
+	return newHandler
+}
+
+// WithGroup returns a new handler which uses passed group name as prefix for all subsequent attributes.
+func (handler *SlogHandler) WithGroup(name string) slog.Handler {
+
+	if name == "" {
+		return handler
+	}
+	newHandler := handler.clone()
+	newHandler.groups = append(newHandler.groups, name)
+	return newHandler
+}
+
+// Flush will force the shipper to deliver all remaining log messages.
+func (handler *SlogHandler) Flush() error {
+	return handler.shipper.Flush()
+}
+
+// clone returns a copy of current handler.
+func (handler *SlogHandler) clone() *SlogHandler {
+
+	groups := make([]string, len(handler.groups), len(handler.groups)+1)
+	copy(groups, handler.groups)
+	return &SlogHandler{
+		logLevel:  handler.logLevel,
+		context:   handler.context,
+		groups:    groups,
+		formatter: handler.formatter,
+		shipper:   handler.shipper,
+	}
+}
+
+// appendAttr converts passed attribute to fields and appends them to given fields.
+// Keys of groups are joined with a dot, empty attributes are ignored
+// and groups without a key are inlined.
+func appendAttr(fields []Field, groups []string, attr slog.Attr) []Field {
+
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupAttrs := attr.Value.Group()
+		if len(groupAttrs) == 0 {
+			return fields
+		}
+		if attr.Key != "" {
+			groups = append(groups[:len(groups):len(groups)], attr.Key)
+		}
+		for _, groupAttr := range groupAttrs {
+			fields = appendAttr(fields, groups, groupAttr)
+		}
+		return fields
+	}
+
+	key := attr.Key
+	if len(groups) > 0 {
+		key = strings.Join(groups, ".") + "." + key
+	}
+	return append(fields, fieldFromSlogValue(key, attr.Value))
+}
+
+// fieldFromSlogValue converts passed slog value into a typed field.
+func fieldFromSlogValue(key string, value slog.Value) Field {
+
+	switch value.Kind() {
+	case slog.KindString:
+		return String(key, value.String())
+	case slog.KindInt64:
+		return Int64(key, value.Int64())
+	case slog.KindFloat64:
+		return Float64(key, value.Float64())
+	case slog.KindBool:
+		return Bool(key, value.Bool())
+	case slog.KindDuration:
+		return Duration(key, value.Duration())
+	case slog.KindTime:
+		return Time(key, value.Time())
+	default:
+		return Any(key, value.Any())
+	}
+}
+
+// NewLoggerFromSlogHandler returns a logger which passes all log messages to given slog.Handler.
+func NewLoggerFromSlogHandler(handler slog.Handler) Logger {
+	return &SlogLogger{
+		handler: handler,
+		ctx:     context.Background(),
+	}
+}
+
+// log creates a slog record with passed values and forwards it to the handler.
+// Fields of the log context from current context are added as attributes.
+func (logger *SlogLogger) log(logLevel LogLevel, message string, fields []Field) {
+
+	slogLevel := logLevel.SlogLevel()
+	if !logger.handler.Enabled(logger.ctx, slogLevel) {
+		return
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, this function and the logger method calling it.
+	runtime.Callers(3, pcs[:])
+	record := slog.NewRecord(nowFrom(logger.clock), slogLevel, message, pcs[0])
+
+	logContext := getLogContext(logger.ctx)
+	for key, value := range logContext.values {
+		record.AddAttrs(slog.String(key, value))
+	}
+	for _, field := range logContext.fields {
+		record.AddAttrs(slogAttrFromField(field))
+	}
+	if logger.namespace != "" {
+		record.AddAttrs(slog.String(LogCtxNamespace, logger.namespace))
+	}
+	for _, field := range fields {
+		record.AddAttrs(slogAttrFromField(field))
+	}
+
+	if err := logger.handler.Handle(logger.ctx, record); err != nil {
+		log.Println(err)
+	}
+}
+
+// Metric logs passed metric with log level Status. The value is added with the name
+// of the metric as key, its unit is added in group units, together with passed dimensions.
+func (logger *SlogLogger) Metric(name string, value float64, unit MetricUnit, dimensions ...Field) {
+	message, fields := metricFields([]Metric{{Name: name, Value: value, Unit: unit}}, dimensions)
+	logger.log(Status, message, fields)
+}
+
+// Metrics logs all passed metrics in one slog record with log level Status. Values are added with
+// names of metrics as keys and units are added in group units, together with passed dimensions.
+func (logger *SlogLogger) Metrics(metrics []Metric, dimensions ...Field) {
+
+	if len(metrics) == 0 {
+		return
+	}
+	message, fields := metricFields(metrics, dimensions)
+	logger.log(Status, message, fields)
+}
+
+// metricFields returns names of passed metrics as message and passed dimensions, values
+// and units of all metrics as fields.
+func metricFields(metrics []Metric, dimensions []Field) (string, []Field) {
+
+	names := make([]string, 0, len(metrics))
+	fields := make([]Field, 0, len(dimensions)+2*len(metrics))
+	fields = append(fields, dimensions...)
+	for _, metric := range metrics {
+		names = append(names, metric.Name)
+		fields = append(fields, Float64(metric.Name, metric.Value))
+		if metric.Unit != "" {
+			fields = append(fields, String("units."+metric.Name, string(metric.Unit)))
+		}
+	}
+	return strings.Join(names, ", "), fields
+}
+
+// WithContext sets the context passed to the handler.
+func (logger *SlogLogger) WithContext(ctx context.Context) {
+	logger.ctx = ctx
+}
+
+// With returns a new logger using a handler with passed fields as attributes.
+func (logger *SlogLogger) With(fields ...Field) Logger {
+
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		attrs = append(attrs, slogAttrFromField(field))
+	}
+	return &SlogLogger{
+		handler:   logger.handler.WithAttrs(attrs),
+		ctx:       logger.ctx,
+		namespace: logger.namespace,
+		clock:     logger.clock,
+	}
+}
+
+// Named returns a new logger with passed name appended to current namespace, separated by a dot.
+func (logger *SlogLogger) Named(name string) Logger {
+
+	if logger.namespace != "" {
+		name = logger.namespace + "." + name
+	}
+	return &SlogLogger{
+		handler:   logger.handler,
+		ctx:       logger.ctx,
+		namespace: name,
+		clock:     logger.clock,
+	}
+}
+
+// Statusf format given log message for log level Status.
+func (logger *SlogLogger) Statusf(message string, v ...interface{}) {
+	logger.log(Status, fmt.Sprintf(message, v...), nil)
+}
+
+// Status will create a log message with given values for log level Status.
+func (logger *SlogLogger) Status(v ...interface{}) {
+	logger.log(Status, fmt.Sprint(v...), nil)
+}
+
+// Errorf format given log message for log level Error.
+func (logger *SlogLogger) Errorf(message string, v ...interface{}) {
+	logger.log(Error, fmt.Sprintf(message, v...), nil)
+}
+
+// Error will create a log message with given values for log level Error.
+func (logger *SlogLogger) Error(v ...interface{}) {
+	logger.log(Error, fmt.Sprint(v...), nil)
+}
+
+// Infof format given log message for log level Info.
+func (logger *SlogLogger) Infof(message string, v ...interface{}) {
+	logger.log(Info, fmt.Sprintf(message, v...), nil)
+}
+
+// Info will create a log message with given values for log level Info.
+func (logger *SlogLogger) Info(v ...interface{}) {
+	logger.log(Info, fmt.Sprint(v...), nil)
+}
+
+// Debugf format given log message for log level Debug.
+func (logger *SlogLogger) Debugf(message string, v ...interface{}) {
+	logger.log(Debug, fmt.Sprintf(message, v...), nil)
+}
+
+// Debug will create a log message with given values for log level Debug.
+func (logger *SlogLogger) Debug(v ...interface{}) {
+	logger.log(Debug, fmt.Sprint(v...), nil)
+}
+
+// Logf passed log message with given log level.
+func (logger *SlogLogger) Logf(logLevel LogLevel, message string, v ...interface{}) {
+	logger.log(logLevel, fmt.Sprintf(message, v...), nil)
+}
+
+// Log passed log message with given log level.
+func (logger *SlogLogger) Log(logLevel LogLevel, v ...interface{}) {
+	logger.log(logLevel, fmt.Sprint(v...), nil)
+}
+
+// Statusw will create a log message with given fields for log level Status.
+func (logger *SlogLogger) Statusw(message string, keysAndValues ...interface{}) {
+	logger.log(Status, message, fieldsFromKeysAndValues(keysAndValues))
+}
+
+// Errorw will create a log message with given fields for log level Error.
+func (logger *SlogLogger) Errorw(message string, keysAndValues ...interface{}) {
+	logger.log(Error, message, fieldsFromKeysAndValues(keysAndValues))
+}
+
+// Infow will create a log message with given fields for log level Info.
+func (logger *SlogLogger) Infow(message string, keysAndValues ...interface{}) {
+	logger.log(Info, message, fieldsFromKeysAndValues(keysAndValues))
+}
+
+// Debugw will create a log message with given fields for log level Debug.
+func (logger *SlogLogger) Debugw(message string, keysAndValues ...interface{}) {
+	logger.log(Debug, message, fieldsFromKeysAndValues(keysAndValues))
+}
+
+// Logw will create a log message with given fields for passed log level.
+func (logger *SlogLogger) Logw(logLevel LogLevel, message string, keysAndValues ...interface{}) {
+	logger.log(logLevel, message, fieldsFromKeysAndValues(keysAndValues))
+}
+
+// Flush will call Flush of used handler, if it provides such a method.
+func (logger *SlogLogger) Flush() {
+
+	switch handler := logger.handler.(type) {
+	case interface{ Flush() }:
+		handler.Flush()
+	case interface{ Flush() error }:
+		if err := handler.Flush(); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// slogAttrFromField converts passed field into a slog attribute.
+func slogAttrFromField(field Field) slog.Attr {
+
+	switch field.Type {
+	case StringType:
+		return slog.String(field.Key, field.value.(string))
+	case IntType:
+		return slog.Int64(field.Key, field.value.(int64))
+	case FloatType:
+		return slog.Float64(field.Key, field.value.(float64))
+	case BoolType:
+		return slog.Bool(field.Key, field.value.(bool))
+	case TimeType:
+		return slog.Time(field.Key, field.value.(time.Time))
+	case DurationType:
+		return slog.Duration(field.Key, field.value.(time.Duration))
+	default:
+		return slog.Any(field.Key, field.value)
+	}
+}
@@ -0,0 +1,233 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SlogHandlerTestSuite struct {
+	suite.Suite
+}
+
+func TestSlogHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(SlogHandlerTestSuite))
+}
+
+func (suite *SlogHandlerTestSuite) TestCreateHandler() {
+
+	handler := NewSlogHandler(Debug, nil, nil)
+	suite.IsType(&DefaultFormatter{}, handler.(*SlogHandler).formatter)
+	suite.IsType(&StdoutShipper{}, handler.(*SlogHandler).shipper)
+
+	conf := loadConfigFromFile("config/logzio.yml")
+	handler2 := NewSlogHandlerFromConfig(conf, nil)
+	suite.IsType(&LogzioJsonFormatter{}, handler2.(*SlogHandler).formatter)
+	suite.IsType(&LogzioShipper{}, handler2.(*SlogHandler).shipper)
+	suite.Equal(Debug, handler2.(*SlogHandler).logLevel)
+}
+
+func (suite *SlogHandlerTestSuite) TestLevelMapping() {
+
+	suite.Equal(Debug, LogLevelFromSlogLevel(slog.LevelDebug))
+	suite.Equal(Info, LogLevelFromSlogLevel(slog.LevelInfo))
+	suite.Equal(Info, LogLevelFromSlogLevel(slog.LevelWarn))
+	suite.Equal(Error, LogLevelFromSlogLevel(slog.LevelError))
+	suite.Equal(Status, LogLevelFromSlogLevel(LevelStatus))
+
+	for _, logLevel := range []LogLevel{Status, Error, Info, Debug} {
+		suite.Equal(logLevel, LogLevelFromSlogLevel(logLevel.SlogLevel()))
+	}
+
+	handler := NewSlogHandler(Info, nil, nil)
+	suite.True(handler.Enabled(context.Background(), slog.LevelError))
+	suite.True(handler.Enabled(context.Background(), slog.LevelWarn))
+	suite.False(handler.Enabled(context.Background(), slog.LevelDebug))
+}
+
+func (suite *SlogHandlerTestSuite) TestLogging() {
+
+	shipper := newTestShipper().(*testShipper)
+	logger := slog.New(NewSlogHandler(Info, nil, shipper))
+
+	logger.Info("This is a test.", "count", 3)
+	suite.Len(shipper.messages, 1)
+	suite.Equal("Info: This is a test., Context: count:3", shipper.messages[0])
+
+	logger.Debug("This is a test.")
+	suite.Len(shipper.messages, 1)
+
+	logger.With("requestid", "req-1").WithGroup("http").Error("This is a test.", "status", 500, slog.Group("client", "ip", "127.0.0.1"))
+	suite.Len(shipper.messages, 2)
+	suite.Equal("Error: This is a test., Context: http.client.ip:127.0.0.1,http.status:500,requestid:req-1", shipper.messages[1])
+
+	logger.WithGroup("empty").Info("This is a test.", slog.Group("", "inlined", true), slog.Attr{})
+	suite.Len(shipper.messages, 3)
+	suite.Equal("Info: This is a test., Context: empty.inlined:true", shipper.messages[2])
+
+	ctx := LogContextWithValues(context.Background(), map[string]string{LogCtxRequestId: "req-2"})
+	logger.InfoContext(ctx, "This is a test.")
+	suite.Len(shipper.messages, 4)
+	suite.Equal("Info: This is a test., Context: requestid:req-2", shipper.messages[3])
+}
+
+func (suite *SlogHandlerTestSuite) TestLoggingWithJsonFormatter() {
+
+	shipper := newTestShipper().(*testShipper)
+	logger := slog.New(NewSlogHandler(Debug, newLogzioJsonFormatter(), shipper))
+
+	logger.Debug("This is a test.", "elapsed", time.Second, "ok", true)
+	suite.Len(shipper.messages, 1)
+
+	values := make(map[string]interface{})
+	suite.Nil(json.Unmarshal([]byte(shipper.messages[0]), &values))
+	suite.Equal("Debug", values[LogCtxLogLevel])
+	suite.Equal(float64(time.Second), values["elapsed"])
+	suite.Equal(true, values["ok"])
+}
+
+func (suite *SlogHandlerTestSuite) TestHandlerContract() {
+
+	shipper := newTestShipper().(*testShipper)
+	handler := NewSlogHandler(Debug, newLogzioJsonFormatter(), shipper)
+
+	suite.Nil(slogtest.TestHandler(handler, func() []map[string]any {
+		results := []map[string]any{}
+		for _, message := range shipper.messages {
+			values := make(map[string]any)
+			suite.Nil(json.Unmarshal([]byte(message), &values))
+			results = append(results, slogResultForTest(values))
+		}
+		return results
+	}))
+}
+
+func (suite *SlogHandlerTestSuite) TestLoggerFromSlogHandler() {
+
+	records := &recordingSlogHandler{}
+	logger := NewLoggerFromSlogHandler(records)
+
+	logger.Error("This ", "is ", "a ", "test.")
+	logger.Infof("This is the %dst test.", 1)
+	logger.Debugw("This is a test.", "count", 3)
+	logger.Status("This is a test.")
+	suite.Len(records.records, 4)
+	suite.Equal(slog.LevelError, records.records[0].Level)
+	suite.Equal("This is a test.", records.records[0].Message)
+	suite.Equal("This is the 1st test.", records.records[1].Message)
+	suite.Equal(LevelStatus, records.records[3].Level)
+	suite.Equal(map[string]interface{}{"count": int64(3)}, attrsOf(records.records[2]))
+
+	frame, _ := runtime.CallersFrames([]uintptr{records.records[0].PC}).Next()
+	suite.Equal("github.com/tommzn/go-log.(*SlogHandlerTestSuite).TestLoggerFromSlogHandler", frame.Function)
+
+	childLogger := logger.With(String("requestid", "req-1")).Named("billing").Named("invoice")
+	childLogger.WithContext(LogContextWithValues(context.Background(), map[string]string{"key": "val"}))
+	childLogger.Info("This is a test.")
+	suite.Len(records.records, 5)
+	suite.Equal(map[string]interface{}{"key": "val", LogCtxNamespace: "billing.invoice"}, attrsOf(records.records[4]))
+	suite.Equal([]slog.Attr{slog.String("requestid", "req-1")}, records.attrs)
+
+	logger.Flush()
+	suite.True(records.flushed)
+}
+
+func (suite *SlogHandlerTestSuite) TestMetricsFromSlogLogger() {
+
+	records := &recordingSlogHandler{}
+	logger := NewLoggerFromSlogHandler(records)
+
+	logger.Metrics([]Metric{{Name: "latency", Value: 12.5, Unit: UnitMilliseconds}, {Name: "requests", Value: 3}}, String("service", "billing"))
+	suite.Len(records.records, 1)
+	suite.Equal(LevelStatus, records.records[0].Level)
+	suite.Equal("latency, requests", records.records[0].Message)
+	suite.Equal(map[string]interface{}{"service": "billing", "latency": 12.5, "requests": float64(3),
+		"units.latency": string(UnitMilliseconds)}, attrsOf(records.records[0]))
+
+	logger.Metrics([]Metric{})
+	suite.Len(records.records, 1)
+}
+
+func (suite *SlogHandlerTestSuite) TestLoggerFromSlogHandlerWithLevel() {
+
+	shipper := newTestShipper().(*testShipper)
+	logger := NewLoggerFromSlogHandler(NewSlogHandler(Error, nil, shipper))
+
+	logger.Info("This is a test.")
+	suite.Len(shipper.messages, 0)
+
+	logger.Errorw("This is a test.", Int("count", 3))
+	suite.Len(shipper.messages, 1)
+	suite.Equal("Error: This is a test., Context: count:3", shipper.messages[0])
+	logger.Flush()
+}
+
+// recordingSlogHandler is a slog.Handler mock which keeps all records.
+type recordingSlogHandler struct {
+	records []slog.Record
+	attrs   []slog.Attr
+	flushed bool
+}
+
+func (handler *recordingSlogHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (handler *recordingSlogHandler) Handle(_ context.Context, record slog.Record) error {
+	handler.records = append(handler.records, record)
+	return nil
+}
+
+func (handler *recordingSlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler.attrs = append(handler.attrs, attrs...)
+	return handler
+}
+
+func (handler *recordingSlogHandler) WithGroup(string) slog.Handler {
+	return handler
+}
+
+func (handler *recordingSlogHandler) Flush() error {
+	handler.flushed = true
+	return nil
+}
+
+func attrsOf(record slog.Record) map[string]interface{} {
+	attrs := make(map[string]interface{})
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value.Any()
+		return true
+	})
+	return attrs
+}
+
+// slogResultForTest renames built-in keys of passed JSON document to slog keys
+// and converts dotted keys of groups into nested maps, as expected by slogtest.
+func slogResultForTest(values map[string]any) map[string]any {
+
+	builtInKeys := map[string]string{"@timestamp": slog.TimeKey, LogCtxLogLevel: slog.LevelKey, LogCtxMessage: slog.MessageKey}
+	result := make(map[string]any)
+	for key, value := range values {
+		if slogKey, ok := builtInKeys[key]; ok {
+			result[slogKey] = value
+			continue
+		}
+		groups := strings.Split(key, ".")
+		group := result
+		for _, name := range groups[:len(groups)-1] {
+			if _, ok := group[name].(map[string]any); !ok {
+				group[name] = make(map[string]any)
+			}
+			group = group[name].(map[string]any)
+		}
+		group[groups[len(groups)-1]] = value
+	}
+	return result
+}
@@ -1,18 +1,26 @@
 package log
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 func newStdoutShipper() LogShipper {
 	return &StdoutShipper{}
 }
 
-// Send print given log message in stdout.
-func (shipper *StdoutShipper) send(message string) {
-	fmt.Println(message)
+// Ship print payload of given records in stdout.
+func (shipper *StdoutShipper) Ship(ctx context.Context, records []Record) error {
+	for _, record := range records {
+		if _, err := fmt.Println(string(record.Payload)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Flush is not necessary for StdoutShipper, because it
 // prints all log messages directly.
-func (shipper *StdoutShipper) flush() {
-	fmt.Sprintln("Stdout shipper flush!")
+func (shipper *StdoutShipper) Flush() error {
+	return nil
 }
@@ -2,6 +2,7 @@ package log
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
 	"testing"
@@ -22,6 +23,7 @@ func (suite *StdoutShipperTestSuite) TestShipper() {
 	orig_out := os.Stdout
 
 	logMessage := "Debug: Test Message, Context: key1,val1"
+	record := Record{Level: Debug, Message: "Test Message", Payload: []byte(logMessage)}
 
 	shipper := newStdoutShipper()
 	suite.IsType(&StdoutShipper{}, shipper)
@@ -30,7 +32,7 @@ func (suite *StdoutShipperTestSuite) TestShipper() {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	shipper.send(logMessage)
+	suite.Nil(shipper.Ship(context.Background(), []Record{record}))
 
 	outC := make(chan string)
 	go func() {
@@ -43,6 +45,6 @@ func (suite *StdoutShipperTestSuite) TestShipper() {
 	suite.Equal(logMessage+"\n", out)
 
 	// FLuah will have no effect, but should not throw any errors.
-	shipper.flush()
+	suite.Nil(shipper.Flush())
 	os.Stdout = orig_out
 }
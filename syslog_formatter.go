@@ -0,0 +1,245 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	config "github.com/tommzn/go-config"
+)
+
+// SYSLOG_FACILITY is the default facility used by SyslogFormatter.
+// Can be set by config: log.formatter.facility
+const SYSLOG_FACILITY = "user"
+
+// SYSLOG_SD_ID is the default id of the structured data element for log context values.
+// Custom ids require a private enterprise number, 32473 is reserved for documentation.
+// Can be set by config: log.formatter.sdid
+const SYSLOG_SD_ID = "ctx@32473"
+
+// SYSLOG_RFC5424_TIMESTAMP_FORMAT is the timestamp format for RFC 5424 messages, with microsecond precision.
+const SYSLOG_RFC5424_TIMESTAMP_FORMAT = "2006-01-02T15:04:05.000000Z07:00"
+
+// SYSLOG_RFC3164_TIMESTAMP_FORMAT is the timestamp format for RFC 3164 messages.
+const SYSLOG_RFC3164_TIMESTAMP_FORMAT = "Jan _2 15:04:05"
+
+// syslogNilValue is used for empty header fields and empty structured data.
+const syslogNilValue = "-"
+
+// syslogFacilities maps facility names to their numeric codes.
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// newSyslogFormatter returns a new SyslogFormatter. Facility, app name and structured data id
+// can be set by config: log.formatter.facility, log.formatter.appname and log.formatter.sdid.
+// Facility can be a name, e.g. local0, or it's numeric code.
+func newSyslogFormatter(conf config.Config, rfc3164 bool) LogFormatter {
+
+	hostname, _ := os.Hostname()
+	formatter := &SyslogFormatter{
+		facility: syslogFacilities[SYSLOG_FACILITY],
+		hostname: hostname,
+		appName:  filepath.Base(os.Args[0]),
+		procId:   strconv.Itoa(os.Getpid()),
+		sdId:     SYSLOG_SD_ID,
+		rfc3164:  rfc3164,
+	}
+	if conf == nil {
+		return formatter
+	}
+	if facility := conf.Get("log.formatter.facility", nil); facility != nil {
+		formatter.facility = SyslogFacilityByName(*facility)
+	}
+	if appName := conf.Get("log.formatter.appname", nil); appName != nil {
+		formatter.appName = *appName
+	}
+	if sdId := conf.Get("log.formatter.sdid", nil); sdId != nil {
+		formatter.sdId = *sdId
+	}
+	return formatter
+}
+
+// SyslogFacilityByName returns the code of a syslog facility, e.g. 16 for local0.
+// Numeric values are accepted as well. Unknown facilities fall back to user.
+func SyslogFacilityByName(name string) int {
+
+	if facility, ok := syslogFacilities[strings.ToLower(name)]; ok {
+		return facility
+	}
+	if facility, err := strconv.Atoi(name); err == nil && facility >= 0 && facility <= 23 {
+		return facility
+	}
+	return syslogFacilities[SYSLOG_FACILITY]
+}
+
+// Format creates a syslog message for passed record.
+func (formatter *SyslogFormatter) Format(record Record) ([]byte, error) {
+	if formatter.rfc3164 {
+		return []byte(formatter.formatRfc3164(record)), nil
+	}
+	return []byte(formatter.formatRfc5424(record)), nil
+}
+
+// formatRfc5424 creates a message with header and all log context values as structured data:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ID key="value"...] MSG
+// A zero time is sent as nil value.
+func (formatter *SyslogFormatter) formatRfc5424(record Record) string {
+
+	timestamp := syslogNilValue
+	if !record.Time.IsZero() {
+		timestamp = record.Time.Format(SYSLOG_RFC5424_TIMESTAMP_FORMAT)
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s %s %s %s %s",
+		formatter.priority(record.Level),
+		timestamp,
+		syslogHeaderValue(formatter.hostFor(record), 255),
+		syslogHeaderValue(formatter.appName, 48),
+		syslogHeaderValue(formatter.procId, 128),
+		syslogNilValue,
+		formatter.structuredData(record),
+		record.Message)
+}
+
+// formatRfc3164 creates a legacy BSD syslog message. Log context values are appended to
+// the message as key=value pairs: <PRI>TIMESTAMP HOSTNAME TAG[PID]: MSG key=value
+// RFC 3164 requires a timestamp, so current time is used for records without time.
+func (formatter *SyslogFormatter) formatRfc3164(record Record) string {
+
+	timestamp := record.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	builder := &strings.Builder{}
+	for _, key := range sortedContextKeys(record.Context) {
+		value, _ := record.Context.lookup(key)
+		writeLogfmtPair(builder, key, value)
+	}
+	message := record.Message
+	if builder.Len() > 0 {
+		message += " " + builder.String()
+	}
+
+	return fmt.Sprintf("<%d>%s %s %s[%s]: %s",
+		formatter.priority(record.Level),
+		timestamp.Format(SYSLOG_RFC3164_TIMESTAMP_FORMAT),
+		syslogHeaderValue(formatter.hostFor(record), 255),
+		syslogHeaderValue(formatter.appName, 32),
+		formatter.procId,
+		message)
+}
+
+// priority calculates the priority of a message from facility and passed log level.
+func (formatter *SyslogFormatter) priority(logLevel LogLevel) int {
+	return formatter.facility*8 + logLevel.SyslogLevel()
+}
+
+// hostFor returns the hostname from context of passed record or hostname of this formatter.
+func (formatter *SyslogFormatter) hostFor(record Record) string {
+	if hostname, ok := record.Context.lookup(LogCtxHostname); ok && hostname != "" {
+		return hostname
+	}
+	return formatter.hostname
+}
+
+// structuredData creates a structured data element with all log context values as params.
+// Params are sorted by name. Without any context values the nil value is returned.
+func (formatter *SyslogFormatter) structuredData(record Record) string {
+
+	keys := sortedContextKeys(record.Context)
+	if len(keys) == 0 {
+		return syslogNilValue
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString("[" + syslogSdName(formatter.sdId))
+	for _, key := range keys {
+		value, _ := record.Context.lookup(key)
+		builder.WriteString(" " + syslogSdName(key) + `="` + syslogEscapeParamValue(value) + `"`)
+	}
+	builder.WriteString("]")
+	return builder.String()
+}
+
+// sortedContextKeys returns all keys of context values and fields, sorted alphabetically.
+func sortedContextKeys(logContext LogContext) []string {
+
+	keys := []string{}
+	for key := range logContext.values {
+		if indexOfField(logContext.fields, key) < 0 {
+			keys = append(keys, key)
+		}
+	}
+	for _, field := range logContext.fields {
+		keys = append(keys, field.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// syslogHeaderValue returns passed value with all non printable characters and spaces removed,
+// truncated to given max length. Empty values are returned as nil value.
+func syslogHeaderValue(value string, maxLength int) string {
+
+	value = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return -1
+		}
+		return r
+	}, value)
+	if len(value) > maxLength {
+		value = value[:maxLength]
+	}
+	if value == "" {
+		return syslogNilValue
+	}
+	return value
+}
+
+// syslogSdName converts passed value to a valid SD-ID or param name. Characters which are
+// not allowed are replaced by underscores and the name is truncated to 32 characters.
+func syslogSdName(name string) string {
+
+	name = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, name)
+	if len(name) > 32 {
+		name = name[:32]
+	}
+	if name == "" {
+		return "_"
+	}
+	return name
+}
+
+// syslogEscapeParamValue escapes '"', '\' and ']' in passed param value.
+func syslogEscapeParamValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
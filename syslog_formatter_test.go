@@ -0,0 +1,97 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SyslogFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestSyslogFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(SyslogFormatterTestSuite))
+}
+
+func (suite *SyslogFormatterTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/syslog.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	formatter, ok := logger.(*LogHandler).formatter.(*SyslogFormatter)
+	suite.True(ok)
+	suite.Equal(16, formatter.facility)
+	suite.Equal("billing", formatter.appName)
+	suite.Equal("billing@32473", formatter.sdId)
+	suite.False(formatter.rfc3164)
+}
+
+func (suite *SyslogFormatterTestSuite) TestFacilityByName() {
+
+	suite.Equal(16, SyslogFacilityByName("local0"))
+	suite.Equal(3, SyslogFacilityByName("DAEMON"))
+	suite.Equal(23, SyslogFacilityByName("23"))
+	suite.Equal(1, SyslogFacilityByName("24"))
+	suite.Equal(1, SyslogFacilityByName("xxx"))
+}
+
+func (suite *SyslogFormatterTestSuite) TestRfc5424() {
+
+	formatter := suite.formatterForTest(false)
+	logContext := newLogContext(map[string]string{
+		LogCtxRequestId: "req-1",
+		"path":          `C:\tmp\[x]`,
+		"quote":         `say "hi"`,
+	}).AppendFields(Int("count", 3), String("invalid key=", "val"))
+
+	logMessage, err := formatter.Format(suite.recordForTest(Error, logContext))
+	suite.Nil(err)
+	suite.Equal(`<131>1 2021-05-30T12:08:47.123456Z node-1 billing 42 - [ctx@32473 count="3" invalid_key_="val" path="C:\\tmp\\[x\]" quote="say \"hi\"" requestid="req-1"] Test Message`, string(logMessage))
+
+	logMessage, err = formatter.Format(suite.recordForTest(Info, newLogContext(map[string]string{LogCtxHostname: "host 1"})))
+	suite.Nil(err)
+	suite.Equal(`<134>1 2021-05-30T12:08:47.123456Z host1 billing 42 - [ctx@32473 hostname="host 1"] Test Message`, string(logMessage))
+
+	logMessage, err = formatter.Format(suite.recordForTest(Debug, newEmptyLogContext()))
+	suite.Nil(err)
+	suite.Equal(`<135>1 2021-05-30T12:08:47.123456Z node-1 billing 42 - - Test Message`, string(logMessage))
+
+	logMessage, err = formatter.Format(suite.recordForTest(Status, newEmptyLogContext()))
+	suite.Nil(err)
+	suite.Equal(`<133>1 2021-05-30T12:08:47.123456Z node-1 billing 42 - - Test Message`, string(logMessage))
+}
+
+func (suite *SyslogFormatterTestSuite) TestRfc3164() {
+
+	formatter := suite.formatterForTest(true)
+	logContext := newLogContext(map[string]string{LogCtxRequestId: "req-1"}).AppendFields(Int("count", 3))
+
+	logMessage, err := formatter.Format(suite.recordForTest(Error, logContext))
+	suite.Nil(err)
+	suite.Equal(`<131>May 30 12:08:47 node-1 billing[42]: Test Message count=3 requestid=req-1`, string(logMessage))
+
+	logMessage, err = formatter.Format(suite.recordForTest(Debug, newEmptyLogContext()))
+	suite.Nil(err)
+	suite.Equal(`<135>May 30 12:08:47 node-1 billing[42]: Test Message`, string(logMessage))
+}
+
+func (suite *SyslogFormatterTestSuite) formatterForTest(rfc3164 bool) LogFormatter {
+	return &SyslogFormatter{
+		facility: 16,
+		hostname: "node-1",
+		appName:  "billing",
+		procId:   "42",
+		sdId:     SYSLOG_SD_ID,
+		rfc3164:  rfc3164,
+	}
+}
+
+func (suite *SyslogFormatterTestSuite) recordForTest(logLevel LogLevel, logContext LogContext) Record {
+	return Record{
+		Time:    time.Date(2021, 5, 30, 12, 8, 47, 123456789, time.UTC),
+		Level:   logLevel,
+		Message: "Test Message",
+		Context: logContext,
+	}
+}
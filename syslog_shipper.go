@@ -0,0 +1,327 @@
+package log
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	config "github.com/tommzn/go-config"
+)
+
+// SYSLOG_NETWORK is the default network used to send messages to a syslog daemon.
+// Can be set by config: log.syslog.network
+const SYSLOG_NETWORK = "udp"
+
+// SYSLOG_ADDRESS is the default address of a syslog daemon for networks udp, tcp and tcp+tls.
+// Can be set by config: log.syslog.address
+const SYSLOG_ADDRESS = "localhost:514"
+
+// SYSLOG_UNIX_ADDRESS is the default address of a syslog daemon for network unix.
+const SYSLOG_UNIX_ADDRESS = "/dev/log"
+
+// SYSLOG_BUFFER_SIZE is the default number of messages which are buffered while a syslog daemon is not available.
+// Can be set by config: log.syslog.buffersize
+const SYSLOG_BUFFER_SIZE = 1000
+
+// SYSLOG_TIMEOUT is the default timeout to connect to a syslog daemon and to write messages.
+// Can be set by config: log.syslog.timeout
+const SYSLOG_TIMEOUT = 5 * time.Second
+
+// SYSLOG_RETRY_INTERVAL is the default time to wait before sending buffered messages again after a failure.
+// Can be set by config: log.syslog.retryinterval
+const SYSLOG_RETRY_INTERVAL = 5 * time.Second
+
+// NewSyslogShipper returns a shipper which sends payload of records to a syslog daemon at passed address.
+// Network is one of udp, tcp, tcp+tls or unix. For TCP and TLS messages are framed by octet counting
+// as defined by RFC 6587. An empty address will use SYSLOG_ADDRESS, or SYSLOG_UNIX_ADDRESS for unix.
+// Passed TLS config is used for tcp+tls, only. Messages are buffered and sent by a background sender,
+// so shipping a record never waits for the syslog daemon. If a message can't be sent, the sender
+// reconnects and tries again after a retry interval. Payload without priority gets one, calculated by
+// facility user and LogLevel.SyslogLevel of a record. The background sender runs until the shipper
+// is closed, so a shipper has to be closed explicitly if it's no longer used.
+func NewSyslogShipper(network string, address string, tlsConfig *tls.Config) LogShipper {
+
+	if address == "" {
+		address = SYSLOG_ADDRESS
+		if network == "unix" {
+			address = SYSLOG_UNIX_ADDRESS
+		}
+	}
+	shipper := &SyslogShipper{
+		network:       network,
+		address:       address,
+		tlsConfig:     tlsConfig,
+		facility:      syslogFacilities[SYSLOG_FACILITY],
+		bufferSize:    SYSLOG_BUFFER_SIZE,
+		timeout:       SYSLOG_TIMEOUT,
+		retryInterval: SYSLOG_RETRY_INTERVAL,
+		pending:       make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	go shipper.sendInBackground()
+	return shipper
+}
+
+// newSyslogShipperFromConfig creates a syslog shipper with settings defined by config, e.g.
+//
+//	log:
+//	  shipper: syslog
+//	  syslog:
+//	    network: tcp+tls
+//	    address: logs.example.com:6514
+//	    buffersize: 1000
+//	    timeout: 5s
+//	    retryinterval: 5s
+//	    tls:
+//	      ca: /etc/ssl/certs/syslog-ca.pem
+//	      insecureskipverify: false
+//
+// Facility for messages without priority is taken from log.formatter.facility.
+func newSyslogShipperFromConfig(conf config.Config) LogShipper {
+
+	network := conf.Get("log.syslog.network", config.AsStringPtr(SYSLOG_NETWORK))
+	address := conf.Get("log.syslog.address", config.AsStringPtr(""))
+	shipper := NewSyslogShipper(*network, *address, syslogTlsConfigFromConfig(conf)).(*SyslogShipper)
+	shipper.bufferSize = *conf.GetAsInt("log.syslog.buffersize", config.AsIntPtr(SYSLOG_BUFFER_SIZE))
+	shipper.timeout = *conf.GetAsDuration("log.syslog.timeout", config.AsDurationPtr(SYSLOG_TIMEOUT))
+	shipper.retryInterval = *conf.GetAsDuration("log.syslog.retryinterval", config.AsDurationPtr(SYSLOG_RETRY_INTERVAL))
+	if facility := conf.Get("log.formatter.facility", nil); facility != nil {
+		shipper.facility = SyslogFacilityByName(*facility)
+	}
+	return shipper
+}
+
+// syslogTlsConfigFromConfig creates a TLS config with a CA certificate defined at log.syslog.tls.ca
+// and certificate validation can be disabled by log.syslog.tls.insecureskipverify.
+// If the CA certificate can't be loaded, system certificates are used and the error is written to STDERR.
+func syslogTlsConfigFromConfig(conf config.Config) *tls.Config {
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: *conf.GetAsBool("log.syslog.tls.insecureskipverify", config.AsBoolPtr(false)),
+	}
+	if caFile := conf.Get("log.syslog.tls.ca", nil); caFile != nil {
+		certificates, err := os.ReadFile(*caFile)
+		if err != nil {
+			log.Println(err)
+			return tlsConfig
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(certificates) {
+			log.Printf("No certificates found in %s", *caFile)
+			return tlsConfig
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	return tlsConfig
+}
+
+// Ship adds payload of passed records to the buffer and notifies the background sender.
+// It doesn't wait until messages have been sent, errors of the background sender are written to STDERR.
+func (shipper *SyslogShipper) Ship(ctx context.Context, records []Record) error {
+
+	shipper.mutex.Lock()
+	for _, record := range records {
+		shipper.bufferMessage(shipper.message(record))
+	}
+	shipper.mutex.Unlock()
+
+	select {
+	case shipper.pending <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Flush sends all buffered messages and returns an error if a message can't be sent.
+func (shipper *SyslogShipper) Flush() error {
+	return shipper.sendBuffer()
+}
+
+// Close stops the background sender and closes the connection to the syslog daemon.
+// Buffered messages are not sent, use Flush before. It can be called several times.
+func (shipper *SyslogShipper) Close() error {
+
+	shipper.stop.Do(func() {
+		close(shipper.done)
+	})
+	shipper.sendMutex.Lock()
+	defer shipper.sendMutex.Unlock()
+	return shipper.disconnect()
+}
+
+// sendInBackground sends buffered messages each time new messages have been shipped. After a failure
+// it waits for the retry interval, messages shipped in the meantime are buffered.
+func (shipper *SyslogShipper) sendInBackground() {
+
+	for {
+		select {
+		case <-shipper.done:
+			return
+		case <-shipper.pending:
+		}
+		if err := shipper.sendBuffer(); err != nil {
+			log.Println(err)
+			select {
+			case <-shipper.done:
+				return
+			case <-time.After(shipper.retryInterval):
+			}
+			select {
+			case shipper.pending <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// message returns payload of passed record. A priority is added if there's none in payload, e.g.
+// because a formatter for other formats than syslog is used.
+func (shipper *SyslogShipper) message(record Record) []byte {
+
+	if hasSyslogPriority(record.Payload) {
+		return record.Payload
+	}
+	priority := "<" + strconv.Itoa(shipper.facility*8+record.Level.SyslogLevel()) + ">"
+	return append([]byte(priority), record.Payload...)
+}
+
+// bufferMessage appends passed message to the buffer. If the buffer is full, the oldest message is dropped.
+func (shipper *SyslogShipper) bufferMessage(message []byte) {
+
+	if shipper.bufferSize > 0 && len(shipper.buffer) >= shipper.bufferSize {
+		shipper.buffer = shipper.buffer[1:]
+		shipper.dropped++
+	}
+	shipper.buffer = append(shipper.buffer, message)
+}
+
+// sendBuffer sends all buffered messages, oldest first. If sending a message fails, the shipper
+// reconnects and tries again once. Messages which can't be sent are kept in the buffer.
+// The buffer is locked while a message is taken from it only, so records can be shipped
+// while a message is sent.
+func (shipper *SyslogShipper) sendBuffer() error {
+
+	shipper.sendMutex.Lock()
+	defer shipper.sendMutex.Unlock()
+
+	for {
+		shipper.mutex.Lock()
+		if len(shipper.buffer) == 0 {
+			shipper.mutex.Unlock()
+			return nil
+		}
+		message, dropped := shipper.buffer[0], shipper.dropped
+		shipper.mutex.Unlock()
+
+		if err := shipper.send(message); err != nil {
+			if err = shipper.send(message); err != nil {
+				shipper.mutex.Lock()
+				defer shipper.mutex.Unlock()
+				return fmt.Errorf("unable to send syslog message to %s, %d messages buffered: %w", shipper.address, len(shipper.buffer), err)
+			}
+		}
+
+		// The message has been dropped already if the buffer has been full in the meantime.
+		shipper.mutex.Lock()
+		if shipper.dropped == dropped {
+			shipper.buffer[0] = nil
+			shipper.buffer = shipper.buffer[1:]
+		}
+		shipper.mutex.Unlock()
+	}
+}
+
+// send writes passed message to current connection, a new connection is established if necessary.
+// The connection is closed if writing fails.
+func (shipper *SyslogShipper) send(message []byte) error {
+
+	if shipper.conn == nil {
+		if err := shipper.connect(); err != nil {
+			return err
+		}
+	}
+	if err := shipper.conn.SetWriteDeadline(time.Now().Add(shipper.timeout)); err != nil {
+		shipper.disconnect()
+		return err
+	}
+	if _, err := shipper.conn.Write(shipper.frame(message)); err != nil {
+		shipper.disconnect()
+		return err
+	}
+	return nil
+}
+
+// connect establishes a connection to the syslog daemon. For unix sockets a datagram socket
+// is used, with a fall back to a stream socket.
+func (shipper *SyslogShipper) connect() error {
+
+	var conn net.Conn
+	var err error
+	switch shipper.network {
+	case "tcp+tls":
+		dialer := &net.Dialer{Timeout: shipper.timeout}
+		conn, err = tls.DialWithDialer(dialer, "tcp", shipper.address, shipper.tlsConfig)
+	case "unix":
+		conn, err = net.DialTimeout("unixgram", shipper.address, shipper.timeout)
+		if err != nil {
+			conn, err = net.DialTimeout("unix", shipper.address, shipper.timeout)
+		}
+	default:
+		conn, err = net.DialTimeout(shipper.network, shipper.address, shipper.timeout)
+	}
+	if err != nil {
+		return err
+	}
+	shipper.conn = conn
+	return nil
+}
+
+// disconnect closes current connection, if there's any.
+func (shipper *SyslogShipper) disconnect() error {
+
+	if shipper.conn == nil {
+		return nil
+	}
+	err := shipper.conn.Close()
+	shipper.conn = nil
+	return err
+}
+
+// frame returns passed message framed for current connection. Messages sent over TCP are prefixed
+// by their length (octet counting, RFC 6587), messages sent over a unix stream socket are terminated
+// by a newline and datagrams are sent as they are.
+func (shipper *SyslogShipper) frame(message []byte) []byte {
+
+	switch shipper.conn.RemoteAddr().Network() {
+	case "tcp", "tcp4", "tcp6":
+		return append([]byte(strconv.Itoa(len(message))+" "), message...)
+	case "unix":
+		if len(message) == 0 || message[len(message)-1] != '\n' {
+			return append(append([]byte{}, message...), '\n')
+		}
+	}
+	return message
+}
+
+// hasSyslogPriority returns true if passed message starts with a priority, e.g. <14>.
+func hasSyslogPriority(message []byte) bool {
+
+	if len(message) < 3 || message[0] != '<' {
+		return false
+	}
+	for i := 1; i < len(message) && i <= 4; i++ {
+		if message[i] == '>' {
+			return i > 1
+		}
+		if message[i] < '0' || message[i] > '9' {
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package log
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SyslogShipperTestSuite struct {
+	suite.Suite
+}
+
+func TestSyslogShipperTestSuite(t *testing.T) {
+	suite.Run(t, new(SyslogShipperTestSuite))
+}
+
+func (suite *SyslogShipperTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/syslog_shipper.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&SyslogFormatter{}, logger.(*LogHandler).formatter)
+	suite.IsType(&SyslogShipper{}, logger.(*LogHandler).shipper)
+
+	shipper := logger.(*LogHandler).shipper.(*SyslogShipper)
+	defer shipper.Close()
+	suite.Equal("tcp", shipper.network)
+	suite.Equal("localhost:5514", shipper.address)
+	suite.Equal(10, shipper.bufferSize)
+	suite.Equal(time.Second, shipper.timeout)
+	suite.Equal(2*time.Second, shipper.retryInterval)
+	suite.Equal(16, shipper.facility)
+	suite.True(shipper.tlsConfig.InsecureSkipVerify)
+
+	suite.Equal(SYSLOG_UNIX_ADDRESS, NewSyslogShipper("unix", "", nil).(*SyslogShipper).address)
+	suite.Equal(SYSLOG_ADDRESS, NewSyslogShipper("udp", "", nil).(*SyslogShipper).address)
+}
+
+func (suite *SyslogShipperTestSuite) TestShipOverUdp() {
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	suite.Nil(err)
+	defer listener.Close()
+
+	shipper := NewSyslogShipper("udp", listener.LocalAddr().String(), nil)
+	defer shipper.(*SyslogShipper).Close()
+	suite.Nil(shipper.Ship(context.Background(), recordsForSyslogTest("<14>1 message 1", "message 2")))
+
+	suite.Equal("<14>1 message 1", suite.readDatagram(listener))
+	suite.Equal("<11>message 2", suite.readDatagram(listener))
+}
+
+func (suite *SyslogShipperTestSuite) TestPriorityForStatus() {
+
+	shipper := &SyslogShipper{facility: 16}
+	record := newRecord(Status, "message", newEmptyLogContext())
+	record.Payload = []byte("message")
+	suite.Equal("<133>message", string(shipper.message(record)))
+	record.Level = Info
+	suite.Equal("<134>message", string(shipper.message(record)))
+}
+
+func (suite *SyslogShipperTestSuite) TestShipOverUnixSocket() {
+
+	path := filepath.Join(suite.T().TempDir(), "log")
+	listener, err := net.ListenPacket("unixgram", path)
+	suite.Nil(err)
+	defer listener.Close()
+
+	shipper := NewSyslogShipper("unix", path, nil)
+	defer shipper.(*SyslogShipper).Close()
+	suite.Nil(shipper.Ship(context.Background(), recordsForSyslogTest("<14>1 message 1")))
+
+	suite.Equal("<14>1 message 1", suite.readDatagram(listener))
+}
+
+func (suite *SyslogShipperTestSuite) TestShipOverTcp() {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Nil(err)
+	defer listener.Close()
+	messages := receiveOctetCountedMessages(listener)
+
+	shipper := NewSyslogShipper("tcp", listener.Addr().String(), nil)
+	defer shipper.(*SyslogShipper).Close()
+	suite.Nil(shipper.Ship(context.Background(), recordsForSyslogTest("<14>1 message 1", "<14>1 message\nwith two lines")))
+
+	suite.Equal("<14>1 message 1", suite.receive(messages))
+	suite.Equal("<14>1 message\nwith two lines", suite.receive(messages))
+}
+
+func (suite *SyslogShipperTestSuite) TestShipOverTls() {
+
+	certificate := selfSignedCertificateForTest(suite)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{certificate}})
+	suite.Nil(err)
+	defer listener.Close()
+	messages := receiveOctetCountedMessages(listener)
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(certificate.Leaf)
+	shipper := NewSyslogShipper("tcp+tls", listener.Addr().String(), &tls.Config{RootCAs: rootCAs, ServerName: "localhost"})
+	defer shipper.(*SyslogShipper).Close()
+	suite.Nil(shipper.Ship(context.Background(), recordsForSyslogTest("<14>1 message 1")))
+
+	suite.Equal("<14>1 message 1", suite.receive(messages))
+}
+
+func (suite *SyslogShipperTestSuite) TestBufferAndReconnect() {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Nil(err)
+	address := listener.Addr().String()
+	suite.Nil(listener.Close())
+
+	shipper := NewSyslogShipper("tcp", address, nil).(*SyslogShipper)
+	defer shipper.Close()
+	shipper.bufferSize = 2
+	suite.Nil(shipper.Ship(context.Background(), recordsForSyslogTest("<14>1 message 1")))
+	suite.Nil(shipper.Ship(context.Background(), recordsForSyslogTest("<14>1 message 2", "<14>1 message 3")))
+	suite.NotNil(shipper.Flush())
+	suite.Len(shipper.buffer, 2)
+
+	listener, err = net.Listen("tcp", address)
+	suite.Nil(err)
+	defer listener.Close()
+	messages := receiveOctetCountedMessages(listener)
+
+	suite.Nil(shipper.Flush())
+	suite.Len(shipper.buffer, 0)
+	suite.Equal("<14>1 message 2", suite.receive(messages))
+	suite.Equal("<14>1 message 3", suite.receive(messages))
+}
+
+func (suite *SyslogShipperTestSuite) TestShipDoesNotWaitForSender() {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Nil(err)
+	defer listener.Close()
+	messages := receiveOctetCountedMessages(listener)
+
+	shipper := NewSyslogShipper("tcp", listener.Addr().String(), nil).(*SyslogShipper)
+	defer shipper.Close()
+
+	// A locked send mutex simulates a sender which waits for an unavailable syslog daemon.
+	shipper.sendMutex.Lock()
+	shipped := make(chan error)
+	go func() {
+		shipped <- shipper.Ship(context.Background(), recordsForSyslogTest("<14>1 message 1"))
+	}()
+	select {
+	case err := <-shipped:
+		suite.Nil(err)
+	case <-time.After(time.Second):
+		suite.Fail("Ship waits for sender.")
+	}
+	shipper.sendMutex.Unlock()
+
+	suite.Equal("<14>1 message 1", suite.receive(messages))
+}
+
+func (suite *SyslogShipperTestSuite) TestCloseTwice() {
+
+	shipper := NewSyslogShipper("udp", "", nil).(*SyslogShipper)
+	suite.Nil(shipper.Close())
+	suite.Nil(shipper.Close())
+}
+
+func (suite *SyslogShipperTestSuite) TestHasSyslogPriority() {
+
+	suite.True(hasSyslogPriority([]byte("<14>1 message")))
+	suite.True(hasSyslogPriority([]byte("<191>message")))
+	suite.False(hasSyslogPriority([]byte("message")))
+	suite.False(hasSyslogPriority([]byte("<>message")))
+	suite.False(hasSyslogPriority([]byte("<1912>message")))
+	suite.False(hasSyslogPriority([]byte("<a>message")))
+}
+
+func (suite *SyslogShipperTestSuite) readDatagram(listener net.PacketConn) string {
+
+	buffer := make([]byte, 1024)
+	suite.Nil(listener.SetReadDeadline(time.Now().Add(time.Second)))
+	length, _, err := listener.ReadFrom(buffer)
+	suite.Nil(err)
+	return string(buffer[:length])
+}
+
+func (suite *SyslogShipperTestSuite) receive(messages chan string) string {
+
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(time.Second):
+		suite.Fail("No message received.")
+		return ""
+	}
+}
+
+// receiveOctetCountedMessages accepts connections of passed listener and
+// passes all received messages, framed by octet counting, to returned channel.
+func receiveOctetCountedMessages(listener net.Listener) chan string {
+
+	messages := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					length, err := reader.ReadString(' ')
+					if err != nil {
+						return
+					}
+					size, _ := strconv.Atoi(strings.TrimSpace(length))
+					message := make([]byte, size)
+					if _, err := io.ReadFull(reader, message); err != nil {
+						return
+					}
+					messages <- string(message)
+				}
+			}(conn)
+		}
+	}()
+	return messages
+}
+
+func recordsForSyslogTest(payloads ...string) []Record {
+	records := []Record{}
+	for _, payload := range payloads {
+		record := newRecord(Error, payload, newEmptyLogContext())
+		record.Payload = []byte(payload)
+		records = append(records, record)
+	}
+	return records
+}
+
+// selfSignedCertificateForTest creates a certificate for localhost.
+func selfSignedCertificateForTest(suite *SyslogShipperTestSuite) tls.Certificate {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Nil(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	suite.Nil(err)
+	leaf, err := x509.ParseCertificate(der)
+	suite.Nil(err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
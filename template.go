@@ -0,0 +1,122 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	config "github.com/tommzn/go-config"
+)
+
+// templateFuncs are helper functions available in templates of a TemplateFormatter.
+var templateFuncs = template.FuncMap{
+	"formatTime": func(layout string, t time.Time) string { return t.Format(layout) },
+	"utc":        func(t time.Time) time.Time { return t.UTC() },
+	"json":       templateJson,
+	"padRight":   func(length int, value interface{}) string { return padRight(fmt.Sprint(value), length) },
+	"padLeft":    templatePadLeft,
+	"upper":      func(value interface{}) string { return strings.ToUpper(fmt.Sprint(value)) },
+	"lower":      func(value interface{}) string { return strings.ToLower(fmt.Sprint(value)) },
+}
+
+// templateData is passed to a template for each record. Beside all values of a record
+// it provides a Field method to lookup context values and fields by key.
+type templateData struct {
+	Record
+	timestamp TimestampFormat
+}
+
+// NewTemplateFormatter returns a formatter which uses passed text/template to create log messages.
+// A template can access all values of a Record, e.g. {{.Time}} [{{.Level}}] {{.Namespace}} {{.Message}},
+// context values or fields with {{.Field "requestid"}} and the time of a record in configured
+// timestamp format with {{.Timestamp}}. Available helper functions are
+// formatTime, utc, json, padRight, padLeft, upper and lower.
+// An error is returned if passed template can not be parsed or fails for a sample record.
+func NewTemplateFormatter(text string) (LogFormatter, error) {
+
+	tmpl, err := template.New("log").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log template %q: %w", text, err)
+	}
+
+	formatter := &TemplateFormatter{template: tmpl}
+	sampleRecord := Record{Time: time.Now(), Level: Info, Message: "Sample Message", Context: newEmptyLogContext()}
+	if _, err := formatter.Format(sampleRecord); err != nil {
+		return nil, fmt.Errorf("invalid log template %q: %w", text, err)
+	}
+	return formatter, nil
+}
+
+// newTemplateFormatterFromConfig creates a template formatter with template defined by
+// config: log.formatter.template. If the template is invalid, the error is written to STDERR
+// and DefaultFormatter is used instead.
+func newTemplateFormatterFromConfig(conf config.Config) LogFormatter {
+
+	text := conf.Get("log.formatter.template", config.AsStringPtr(""))
+	formatter, err := NewTemplateFormatter(*text)
+	if err != nil {
+		log.Println(err)
+		return newDefaultFormatter()
+	}
+	return formatter
+}
+
+// setTimestampFormat defines how the time of a record is rendered.
+func (formatter *TemplateFormatter) setTimestampFormat(timestampFormat TimestampFormat) {
+	formatter.timestamp = timestampFormat
+}
+
+// Format executes the template of this formatter for passed record.
+func (formatter *TemplateFormatter) Format(record Record) ([]byte, error) {
+
+	buffer := &bytes.Buffer{}
+	if err := formatter.template.Execute(buffer, templateData{Record: record, timestamp: formatter.timestamp}); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Timestamp returns the time of a record in RFC 3339 format, or using the timestamp format
+// defined for the formatter. An empty string is returned for a zero time.
+func (data templateData) Timestamp() string {
+	if data.Time.IsZero() {
+		return ""
+	}
+	return data.timestamp.format(data.Time, time.RFC3339Nano)
+}
+
+// Field returns the value of a field or context value with passed key.
+// An empty string is returned if there's no such key.
+func (data templateData) Field(key string) string {
+	value, _ := data.Context.lookup(key)
+	return value
+}
+
+// templateJson encodes passed value as JSON. Fields are encoded with their native values.
+func templateJson(value interface{}) (string, error) {
+
+	switch val := value.(type) {
+	case Field:
+		value = val.jsonValue()
+	case LogLevel:
+		value = val.String()
+	case error:
+		value = val.Error()
+	}
+	content, err := json.Marshal(value)
+	return string(content), err
+}
+
+// templatePadLeft prepends spaces to passed value up to given length.
+func templatePadLeft(length int, value interface{}) string {
+
+	text := fmt.Sprint(value)
+	if padding := length - len([]rune(text)); padding > 0 {
+		return strings.Repeat(" ", padding) + text
+	}
+	return text
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	config "github.com/tommzn/go-config"
+)
+
+type TemplateFormatterTestSuite struct {
+	suite.Suite
+}
+
+func TestTemplateFormatterTestSuite(t *testing.T) {
+	suite.Run(t, new(TemplateFormatterTestSuite))
+}
+
+func (suite *TemplateFormatterTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/template.yml")
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.IsType(&TemplateFormatter{}, logger.(*LogHandler).formatter)
+
+	logMessage, err := logger.(*LogHandler).formatter.Format(suite.recordForTest())
+	suite.Nil(err)
+	suite.Equal("2021-05-30T12:08:47Z [Error] billing Test Message", string(logMessage))
+
+	invalidConf, _ := config.NewStaticConfigSource("log:\n  formatter:\n    type: template\n    template: \"{{.Message\"\n").Load()
+	suite.IsType(&DefaultFormatter{}, NewLoggerFromConfig(invalidConf, nil).(*LogHandler).formatter)
+}
+
+func (suite *TemplateFormatterTestSuite) TestInvalidTemplates() {
+
+	_, err := NewTemplateFormatter("{{.Message")
+	suite.ErrorContains(err, `invalid log template "{{.Message"`)
+
+	_, err = NewTemplateFormatter("{{.Unknown}}")
+	suite.ErrorContains(err, "can't evaluate field Unknown")
+
+	_, err = NewTemplateFormatter("{{unknown .Message}}")
+	suite.ErrorContains(err, `function "unknown" not defined`)
+}
+
+func (suite *TemplateFormatterTestSuite) TestHelperFunctions() {
+
+	formatter, err := NewTemplateFormatter(`{{padRight 6 (upper .Level)}}|{{padLeft 4 .Sequence}}|{{.Field "requestid"}}|{{.Field "missing"}}|{{json .Message}}|{{json .Error}}|{{lower .Namespace}}|{{.Caller}}`)
+	suite.Nil(err)
+
+	logMessage, err := formatter.Format(suite.recordForTest())
+	suite.Nil(err)
+	suite.Equal(`ERROR |  42|req-1||"Test Message"|"failed"|billing|main.go:12`, string(logMessage))
+}
+
+func (suite *TemplateFormatterTestSuite) recordForTest() Record {
+	return Record{
+		Time:      time.Date(2021, 5, 30, 14, 8, 47, 0, time.FixedZone("CEST", 7200)),
+		Level:     Error,
+		Message:   "Test Message",
+		Context:   newLogContext(map[string]string{LogCtxRequestId: "req-1"}),
+		Namespace: "billing",
+		Error:     errors.New("failed"),
+		Caller:    Caller{File: "main.go", Line: 12},
+		Sequence:  42,
+	}
+}
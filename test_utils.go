@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/aws/aws-lambda-go/lambdacontext"
 	config "github.com/tommzn/go-config"
@@ -19,19 +20,36 @@ func newTestShipper() LogShipper {
 	return &testShipper{messages: []string{}}
 }
 
-func (shipper *testShipper) send(message string) {
-	shipper.messages = append(shipper.messages, message)
+func (shipper *testShipper) Ship(ctx context.Context, records []Record) error {
+	for _, record := range records {
+		shipper.messages = append(shipper.messages, string(record.Payload))
+	}
+	return nil
 }
 
-func (shipper *testShipper) flush() {
+func (shipper *testShipper) Flush() error {
 	fmt.Println("Test Shipper flush!")
+	return nil
 }
 
-// testClient is a HTTP client mock for testing.
+// testHook is a mock for testing which keeps all records.
+type testHook struct {
+	records []Record
+	err     error
+}
+
+func (hook *testHook) Fire(record Record) error {
+	hook.records = append(hook.records, record)
+	return hook.err
+}
+
+// testClient is a HTTP client mock for testing. Requests are recorded,
+// use recordedRequests to read them while requests are sent.
 type testClient struct {
 	requests []*http.Request
 	response *http.Response
 	err      error
+	mutex    sync.Mutex
 }
 
 func newHttpTestClient(response *http.Response, err error) httpClient {
@@ -39,10 +57,19 @@ func newHttpTestClient(response *http.Response, err error) httpClient {
 }
 
 func (client *testClient) Do(req *http.Request) (*http.Response, error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
 	client.requests = append(client.requests, req)
 	return client.response, client.err
 }
 
+// recordedRequests returns a copy of all requests sent so far.
+func (client *testClient) recordedRequests() []*http.Request {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return append([]*http.Request{}, client.requests...)
+}
+
 func loadConfigFromFile(fileName string) config.Config {
 	configSource := config.NewFileConfigSource(&fileName)
 	config, _ := configSource.Load()
@@ -0,0 +1,199 @@
+package log
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	config "github.com/tommzn/go-config"
+)
+
+// TIMESTAMP_EPOCH can be used as layout or precision to render timestamps as Unix time.
+// Precision defines the unit of Unix time, default is seconds.
+const TIMESTAMP_EPOCH = "epoch"
+
+// timestampLayouts are named layouts which can be used in config instead of a Go time layout.
+var timestampLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"datetime":    time.DateTime,
+	"stampmilli":  time.StampMilli,
+}
+
+// timestampPrecisions maps names of precisions to their duration.
+var timestampPrecisions = map[string]time.Duration{
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+// timestampFormatFromConfig returns a timestamp format defined by config, e.g.
+//
+//	log:
+//	  formatter:
+//	    type: json
+//	    timestamp:
+//	      layout: rfc3339
+//	      precision: ms
+//	      timezone: Europe/Berlin
+//
+// Layout can be a Go time layout, epoch or one of rfc3339, rfc3339nano, rfc1123, rfc1123z, datetime
+// and stampmilli. A Go time layout has to contain at least one element of the reference time, e.g. 2006.
+// Supported precisions are s, ms, us (or µs), ns and epoch. Time zone is a name of the IANA time zone
+// database, UTC or Local. Invalid values are ignored and written to STDERR.
+// GELF, syslog and OTLP formatters ignore a timestamp format, because their protocols define
+// the format of timestamps.
+// Returns false if there's no timestamp format defined at all.
+func timestampFormatFromConfig(conf config.Config) (TimestampFormat, bool) {
+
+	layout := conf.Get("log.formatter.timestamp.layout", nil)
+	precision := conf.Get("log.formatter.timestamp.precision", nil)
+	timezone := conf.Get("log.formatter.timestamp.timezone", nil)
+	if layout == nil && precision == nil && timezone == nil {
+		return TimestampFormat{}, false
+	}
+
+	timestampFormat := TimestampFormat{}
+	if layout != nil {
+		if namedLayout, ok := timestampLayouts[strings.ToLower(*layout)]; ok {
+			timestampFormat.layout = namedLayout
+		} else if strings.ToLower(*layout) == TIMESTAMP_EPOCH {
+			timestampFormat.epoch = true
+		} else if isTimeLayout(*layout) {
+			timestampFormat.layout = *layout
+		} else {
+			log.Printf("Unsupported timestamp layout: %s", *layout)
+		}
+	}
+	if precision != nil {
+		if strings.ToLower(*precision) == TIMESTAMP_EPOCH {
+			timestampFormat.epoch = true
+		} else if duration, ok := timestampPrecisions[strings.ToLower(*precision)]; ok {
+			timestampFormat.precision = duration
+		} else {
+			log.Printf("Unsupported timestamp precision: %s", *precision)
+		}
+	}
+	if timezone != nil {
+		if location, err := time.LoadLocation(*timezone); err == nil {
+			timestampFormat.location = location
+		} else {
+			log.Println(err)
+		}
+	}
+	return timestampFormat, true
+}
+
+// timeLayoutElements are elements of the reference time used in Go time layouts. Elements with a single
+// digit, e.g. 3 for the hour, are not included, because they're part of many words which are no layouts.
+var timeLayoutElements = []string{
+	"2006", "Jan", "Mon", "MST", "Z07", "-07", "01", "02", "_2", "03", "04", "05", "15", "PM", "pm", ".000", ".999", ",000", ",999",
+}
+
+// isTimeLayout returns true if passed layout contains an element of the reference time, e.g. 2006 or 15.
+func isTimeLayout(layout string) bool {
+	for _, element := range timeLayoutElements {
+		if strings.Contains(layout, element) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDefined returns true if any setting of a timestamp format differs from its zero value.
+func (timestampFormat TimestampFormat) isDefined() bool {
+	return timestampFormat.layout != "" || timestampFormat.epoch ||
+		timestampFormat.precision > 0 || timestampFormat.location != nil
+}
+
+// format converts passed time to a string using the layout of this timestamp format,
+// or given default layout if there's no layout or precision defined.
+func (timestampFormat TimestampFormat) format(t time.Time, defaultLayout string) string {
+
+	t = timestampFormat.convert(t)
+	if timestampFormat.epoch {
+		return strconv.FormatInt(timestampFormat.unixTime(t), 10)
+	}
+	return t.Format(timestampFormat.layoutFor(defaultLayout))
+}
+
+// value returns passed time as it should be rendered by JSON formatters,
+// which is a number for Unix time and a string otherwise.
+func (timestampFormat TimestampFormat) value(t time.Time, defaultLayout string) interface{} {
+
+	if timestampFormat.epoch {
+		return timestampFormat.unixTime(timestampFormat.convert(t))
+	}
+	return timestampFormat.format(t, defaultLayout)
+}
+
+// convert changes time zone of passed time and truncates it to defined precision.
+func (timestampFormat TimestampFormat) convert(t time.Time) time.Time {
+
+	if timestampFormat.location != nil {
+		t = t.In(timestampFormat.location)
+	}
+	if timestampFormat.precision > 0 {
+		t = t.Truncate(timestampFormat.precision)
+	}
+	return t
+}
+
+// layoutFor returns the layout of this timestamp format. Without a layout RFC 3339 with
+// fractional seconds depending on defined precision is used, or passed default layout
+// if there's no precision, too. If a time zone is defined, a literal Z at the end of
+// passed default layout is replaced by the offset of the time zone.
+func (timestampFormat TimestampFormat) layoutFor(defaultLayout string) string {
+
+	if timestampFormat.layout != "" {
+		return timestampFormat.layout
+	}
+	switch timestampFormat.precision {
+	case 0:
+		if timestampFormat.location != nil && strings.HasSuffix(defaultLayout, "Z") {
+			return strings.TrimSuffix(defaultLayout, "Z") + "Z07:00"
+		}
+		return defaultLayout
+	case time.Millisecond:
+		return "2006-01-02T15:04:05.000Z07:00"
+	case time.Microsecond:
+		return "2006-01-02T15:04:05.000000Z07:00"
+	case time.Nanosecond:
+		return "2006-01-02T15:04:05.000000000Z07:00"
+	default:
+		return time.RFC3339
+	}
+}
+
+// unixTime returns passed time as Unix time, using precision as unit. Default unit is seconds.
+func (timestampFormat TimestampFormat) unixTime(t time.Time) int64 {
+
+	switch timestampFormat.precision {
+	case time.Millisecond:
+		return t.UnixMilli()
+	case time.Microsecond:
+		return t.UnixMicro()
+	case time.Nanosecond:
+		return t.UnixNano()
+	default:
+		return t.Unix()
+	}
+}
+
+// Now calls the clock function.
+func (clock ClockFunc) Now() time.Time {
+	return clock()
+}
+
+// nowFrom returns current time of passed clock, or system time if there's no clock.
+func nowFrom(clock Clock) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
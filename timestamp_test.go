@@ -0,0 +1,171 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	config "github.com/tommzn/go-config"
+)
+
+type TimestampTestSuite struct {
+	suite.Suite
+	time time.Time
+}
+
+func TestTimestampTestSuite(t *testing.T) {
+	suite.Run(t, new(TimestampTestSuite))
+}
+
+func (suite *TimestampTestSuite) SetupTest() {
+	suite.time = time.Date(2021, 5, 30, 12, 8, 47, 123456789, time.UTC)
+}
+
+func (suite *TimestampTestSuite) TestCreateFromConfig() {
+
+	conf := loadConfigFromFile("config/timestamp.yml")
+	timestampFormat, ok := timestampFormatFromConfig(conf)
+	suite.True(ok)
+	suite.Equal(time.RFC3339, timestampFormat.layout)
+	suite.Equal(time.Millisecond, timestampFormat.precision)
+	suite.Equal("Europe/Berlin", timestampFormat.location.String())
+
+	logger := NewLoggerFromConfig(conf, nil)
+	suite.Equal(timestampFormat, logger.(*LogHandler).formatter.(*LogzioJsonFormatter).timestamp)
+
+	_, ok = timestampFormatFromConfig(loadConfigFromFile("config/logfmt.yml"))
+	suite.False(ok)
+}
+
+func (suite *TimestampTestSuite) TestLayoutFromConfig() {
+
+	for layout, expected := range map[string]string{
+		"RFC3339Nano":     time.RFC3339Nano,
+		"2006-01-02":      "2006-01-02",
+		"Jan _2 15:04:05": "Jan _2 15:04:05",
+		"rfc3339milli":    "",
+		"timestamp":       "",
+	} {
+		conf, err := config.NewStaticConfigSource("log:\n  formatter:\n    timestamp:\n      layout: \"" + layout + "\"\n").Load()
+		suite.Nil(err)
+		timestampFormat, ok := timestampFormatFromConfig(conf)
+		suite.True(ok)
+		suite.Equal(expected, timestampFormat.layout, layout)
+	}
+
+	suite.True(isTimeLayout(time.Kitchen))
+	suite.False(isTimeLayout("rfc3339milli"))
+}
+
+func (suite *TimestampTestSuite) TestFormat() {
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	suite.Nil(err)
+
+	suite.Equal("2021-05-30T12:08:47.123Z", TimestampFormat{}.format(suite.time, LOGZIO_TIMESTAMP_FORMAT))
+	suite.Equal("2021-05-30T12:08:47Z", TimestampFormat{layout: time.RFC3339}.format(suite.time, LOGZIO_TIMESTAMP_FORMAT))
+	suite.Equal("2021-05-30T12:08:47.123Z", TimestampFormat{precision: time.Millisecond}.format(suite.time, time.RFC3339Nano))
+	suite.Equal("2021-05-30T12:08:47.123456Z", TimestampFormat{precision: time.Microsecond}.format(suite.time, time.RFC3339Nano))
+	suite.Equal("2021-05-30T12:08:47.123456789Z", TimestampFormat{precision: time.Nanosecond}.format(suite.time, time.RFC3339Nano))
+	suite.Equal("2021-05-30T14:08:47.123+02:00", TimestampFormat{precision: time.Millisecond, location: berlin}.format(suite.time, time.RFC3339Nano))
+	suite.Equal("1622376527", TimestampFormat{epoch: true}.format(suite.time, time.RFC3339Nano))
+	suite.Equal("2021-05-30T14:08:47.123+02:00", TimestampFormat{location: berlin}.format(suite.time, LOGZIO_TIMESTAMP_FORMAT))
+	suite.Equal("2021-05-30T12:08:47.123Z", TimestampFormat{location: time.UTC}.format(suite.time, LOGZIO_TIMESTAMP_FORMAT))
+}
+
+func (suite *TimestampTestSuite) TestValue() {
+
+	suite.Equal("2021-05-30T12:08:47.123Z", TimestampFormat{}.value(suite.time, LOGZIO_TIMESTAMP_FORMAT))
+	suite.Equal(int64(1622376527), TimestampFormat{epoch: true}.value(suite.time, time.RFC3339Nano))
+	suite.Equal(int64(1622376527123), TimestampFormat{epoch: true, precision: time.Millisecond}.value(suite.time, time.RFC3339Nano))
+	suite.Equal(int64(1622376527123456), TimestampFormat{epoch: true, precision: time.Microsecond}.value(suite.time, time.RFC3339Nano))
+	suite.Equal(int64(1622376527123456789), TimestampFormat{epoch: true, precision: time.Nanosecond}.value(suite.time, time.RFC3339Nano))
+}
+
+func (suite *TimestampTestSuite) TestFormatterWithTimestampFormat() {
+
+	record := newRecord(Info, "Test Message", newEmptyLogContext())
+	record.Time = suite.time
+	epochMillis := TimestampFormat{epoch: true, precision: time.Millisecond}
+
+	defaultFormatter := &DefaultFormatter{}
+	logMessage, _ := defaultFormatter.Format(record)
+	suite.Equal("Info: Test Message, Context: ", string(logMessage))
+	defaultFormatter.setTimestampFormat(TimestampFormat{precision: time.Second})
+	logMessage, _ = defaultFormatter.Format(record)
+	suite.Equal("2021-05-30T12:08:47Z Info: Test Message, Context: ", string(logMessage))
+
+	jsonFormatter := &LogzioJsonFormatter{timestamp: epochMillis}
+	logMessage, _ = jsonFormatter.Format(record)
+	suite.Contains(string(logMessage), `"@timestamp":1622376527123`)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	suite.Nil(err)
+	jsonFormatter = &LogzioJsonFormatter{timestamp: TimestampFormat{location: berlin}}
+	logMessage, _ = jsonFormatter.Format(record)
+	suite.Contains(string(logMessage), `"@timestamp":"2021-05-30T14:08:47.123+02:00"`)
+
+	logfmtFormatter := &LogfmtFormatter{timestamp: TimestampFormat{precision: time.Millisecond}}
+	logMessage, _ = logfmtFormatter.Format(record)
+	suite.Contains(string(logMessage), `time=2021-05-30T12:08:47.123Z`)
+
+	templateFormatter, err := NewTemplateFormatter("{{.Timestamp}} {{.Message}}")
+	suite.Nil(err)
+	templateFormatter.(*TemplateFormatter).setTimestampFormat(epochMillis)
+	logMessage, _ = templateFormatter.Format(record)
+	suite.Equal("1622376527123 Test Message", string(logMessage))
+}
+
+func (suite *TimestampTestSuite) TestLoggerWithClock() {
+
+	clock := ClockFunc(func() time.Time { return suite.time })
+	shipper := newTestShipper()
+	logger := WithClock(NewLogger(Info, &LogfmtFormatter{}, shipper), clock)
+
+	logger.Info("Test Message")
+	logger.Named("billing").Info("Test Message")
+
+	messages := shipper.(*testShipper).messages
+	suite.Len(messages, 2)
+	suite.Contains(messages[0], "time=2021-05-30T12:08:47.123456789Z")
+	suite.Contains(messages[1], "time=2021-05-30T12:08:47.123456789Z")
+}
+
+func (suite *TimestampTestSuite) TestLoggerWithClockPassesClockToFormatter() {
+
+	clock := ClockFunc(func() time.Time { return suite.time })
+	logger := WithClock(NewLogger(Info, &OtlpJsonFormatter{}, newTestShipper()), clock)
+
+	formatter := logger.(*LogHandler).formatter
+	suite.IsType(&OtlpJsonFormatter{}, formatter)
+	suite.Equal(suite.time, formatter.(*OtlpJsonFormatter).clock.Now())
+}
+
+func (suite *TimestampTestSuite) TestLoggerWithClockPassesClockToOutputs() {
+
+	clock := ClockFunc(func() time.Time { return suite.time })
+	multiShipper := NewMultiShipper(LogOutput{LogLevel: Info, Formatter: &OtlpJsonFormatter{}, Shipper: newTestShipper()})
+	logger := WithClock(NewLogger(Info, nil, multiShipper), clock)
+
+	outputs := logger.(*LogHandler).shipper.(*MultiShipper).outputs
+	suite.Equal(suite.time, outputs[0].Formatter.(*OtlpJsonFormatter).clock.Now())
+	suite.Nil(multiShipper.(*MultiShipper).outputs[0].Formatter.(*OtlpJsonFormatter).clock)
+
+	router := NewRouterShipper(map[string]LogOutput{"default": {LogLevel: Info, Formatter: &OtlpJsonFormatter{}, Shipper: newTestShipper()}}, nil, "default")
+	logger = WithClock(NewLogger(Info, nil, router), clock)
+
+	sinks := logger.(*LogHandler).shipper.(*RouterShipper).sinks
+	suite.Equal(suite.time, sinks["default"].Formatter.(*OtlpJsonFormatter).clock.Now())
+	suite.Equal("default", logger.(*LogHandler).shipper.(*RouterShipper).defaultSink)
+}
+
+func (suite *TimestampTestSuite) TestSlogLoggerWithClock() {
+
+	records := &recordingSlogHandler{}
+	logger := WithClock(NewLoggerFromSlogHandler(records), ClockFunc(func() time.Time { return suite.time }))
+
+	logger.Named("billing").Info("Test Message")
+
+	suite.Len(records.records, 1)
+	suite.Equal(suite.time, records.records[0].Time)
+}
@@ -1,6 +1,13 @@
 package log
 
 import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"text/template"
 	"time"
 
 	secrets "github.com/tommzn/go-secrets"
@@ -43,39 +50,497 @@ const (
 	LogCtxK8sNode = "k8s_node"
 	// LogCtxK8sPod is a context key for a kubernetes pod name.
 	LogCtxK8sPod = "k8s_pod"
+	// LogCtxError is a context key for an error.
+	LogCtxError = "error"
+	// LogCtxTraceId is a context key for the id of a distributed trace.
+	LogCtxTraceId = "traceid"
+	// LogCtxSpanId is a context key for the id of a span in a distributed trace.
+	LogCtxSpanId = "spanid"
 )
 
+// FieldType defines the type of a value attached to a Field.
+type FieldType int
+
+const (
+	// UnknownType is the type of an empty field.
+	UnknownType FieldType = iota
+	// StringType is used for string values.
+	StringType
+	// IntType is used for integer values.
+	IntType
+	// FloatType is used for floating point values.
+	FloatType
+	// BoolType is used for boolean values.
+	BoolType
+	// TimeType is used for timestamps.
+	TimeType
+	// DurationType is used for durations.
+	DurationType
+	// ErrorType is used for errors.
+	ErrorType
+	// AnyType is used for all other values, e.g. nested objects.
+	AnyType
+)
+
+// Field is a typed key/value pair which can be attached to a log context.
+type Field struct {
+
+	// Key is the name of a field.
+	Key string
+
+	// Type defines the kind of value of a field.
+	Type FieldType
+
+	// Value is the native value of a field.
+	value interface{}
+}
+
+// Record is a single log entry passed from a logger to hooks, formatters and shippers.
+// It's created once for each log message, so all stages get the same values.
+type Record struct {
+
+	// Time is the point in time a record has been created. A zero time is unknown,
+	// formatters omit the timestamp then, if their format allows it.
+	Time time.Time
+
+	// Level is the log level of this record.
+	Level LogLevel
+
+	// Message is the log message.
+	Message string
+
+	// Context provides all context values and fields of this record.
+	Context LogContext
+
+	// Namespace is the namespace of the logger which created this record.
+	Namespace string
+
+	// Error is the first error passed as a field, if any.
+	Error error
+
+	// Caller is the source code location a record has been logged at.
+	Caller Caller
+
+	// Sequence is a number which is increased for each record created in this process.
+	Sequence uint64
+
+	// Metrics contains all metrics passed to a logger's Metric or Metrics method.
+	Metrics []Metric
+
+	// Dimensions are keys of context fields which have been passed as dimensions for metrics.
+	Dimensions []string
+
+	// Payload is the log message created by a formatter.
+	// It's set by a logger before a record is passed to a shipper.
+	Payload []byte
+}
+
+// Caller is a location in source code.
+type Caller struct {
+
+	// Function is the fully qualified name of a function.
+	Function string
+
+	// File is the path of a source file.
+	File string
+
+	// Line is the line number in a source file.
+	Line int
+}
+
 // LogContext provides context values for logging.
 type LogContext struct {
 	values map[string]string
+	fields []Field
+}
+
+// ClockFunc is an adapter to use a function as Clock, e.g. ClockFunc(time.Now).
+type ClockFunc func() time.Time
+
+// TimestampFormat defines how formatters render the time of a record.
+// A zero TimestampFormat keeps the default format of a formatter.
+type TimestampFormat struct {
+
+	// Layout is a Go time layout. If it's empty, a formatter uses its default layout.
+	layout string
+
+	// Epoch renders timestamps as Unix time, using precision as unit.
+	epoch bool
+
+	// Precision timestamps are truncated to, e.g. time.Millisecond.
+	precision time.Duration
+
+	// Location is the time zone timestamps are converted to.
+	location *time.Location
+}
+
+// CollisionPolicy defines how JSON formatters handle context values and fields with a key
+// which is reserved by a formatter, e.g. message.
+type CollisionPolicy int
+
+const (
+	// CollisionRename adds a colliding context value with prefix COLLISION_PREFIX, e.g. fields.message.
+	CollisionRename CollisionPolicy = iota
+	// CollisionReject drops a colliding context value and writes a diagnostic to STDERR.
+	CollisionReject
+	// CollisionOverride uses a colliding context value instead of the value of a formatter.
+	CollisionOverride
+)
+
+// keyMapping renames keys in documents of LogzioJsonFormatter and adds a prefix to keys of context values and fields.
+type keyMapping struct {
+
+	// Names of keys defined at log.formatter.fields.<key>, keys without a name are not included.
+	names map[string]string
+
+	// Prefix is prepended to keys of context values and fields which have no mapping.
+	// A prefix ending with a dot adds these values to a nested object instead.
+	prefix string
 }
 
 // DefaultFormatter is a fallback formatter to convert log values into a message.
 type DefaultFormatter struct {
+
+	// Timestamp defines how the time of a record is rendered.
+	timestamp TimestampFormat
 }
 
 // LogzioJsonFormatter will convert passed values to a JSON record suitlable for an import at Logz.io.
 type LogzioJsonFormatter struct {
+
+	// Timestamp defines how the time of a record is rendered.
+	timestamp TimestampFormat
+
+	// Keys renames keys of a record. Keys are used as they are if it's nil.
+	keys *keyMapping
+
+	// Collisions defines how context values with reserved keys are handled.
+	collisions CollisionPolicy
+}
+
+// LogfmtFormatter will convert passed values to a logfmt record, e.g. time=... level=info msg="..."
+type LogfmtFormatter struct {
+
+	// Timestamp defines how the time of a record is rendered.
+	timestamp TimestampFormat
+}
+
+// EcsJsonFormatter will convert passed values to a JSON document using Elastic Common Schema (ECS) field names.
+type EcsJsonFormatter struct {
+
+	// ServiceName is used as service.name. If it's empty, the namespace of a record is used.
+	serviceName string
+
+	// Timestamp defines how the time of a record is rendered.
+	timestamp TimestampFormat
+
+	// Collisions defines how context values with reserved keys are handled.
+	collisions CollisionPolicy
+}
+
+// GelfFormatter will convert passed values to a GELF 1.1 message for Graylog.
+type GelfFormatter struct {
+
+	// Hostname is used as host if there's no hostname in log context.
+	hostname string
+}
+
+// SyslogFormatter will convert passed values to a syslog message as defined by RFC 5424
+// or, in legacy mode, by RFC 3164.
+type SyslogFormatter struct {
+
+	// Facility is the syslog facility used to calculate the priority of a message.
+	facility int
+
+	// Hostname is used if there's no hostname in log context.
+	hostname string
+
+	// AppName identifies the application which created a message.
+	appName string
+
+	// ProcId is the process id added to all messages.
+	procId string
+
+	// SdId is the id of the structured data element used for log context values.
+	sdId string
+
+	// Rfc3164 enables legacy BSD syslog format.
+	rfc3164 bool
+}
+
+// ConsoleFormatter will convert passed values to a human friendly, optionally colored, line for local development.
+type ConsoleFormatter struct {
+
+	// Colors enables ANSI colors.
+	colors bool
+
+	// MessageWidth is the width messages are padded to, to align context values.
+	messageWidth int
+
+	// Timestamp defines how the time of a record is rendered.
+	timestamp TimestampFormat
+}
+
+// TemplateFormatter will convert passed values to a log message using a text/template.
+type TemplateFormatter struct {
+
+	// Template is executed for each record.
+	template *template.Template
+
+	// Timestamp defines how the time of a record is rendered.
+	timestamp TimestampFormat
+}
+
+// CloudLoggingFormatter will convert passed values to structured JSON for Google Cloud Logging.
+type CloudLoggingFormatter struct {
+
+	// ProjectId is used to create the full resource name of traces.
+	projectId string
+
+	// Timestamp defines how the time of a record is rendered.
+	timestamp TimestampFormat
+
+	// Collisions defines how context values with reserved keys are handled.
+	collisions CollisionPolicy
+}
+
+// DatadogJsonFormatter will convert passed values to JSON which can be correlated with Datadog APM traces.
+type DatadogJsonFormatter struct {
+
+	// Service, env and version are used for unified service tagging.
+	service string
+	env     string
+	version string
+
+	// Tags are added as ddtags, each tag in format key:value.
+	tags []string
+
+	// Timestamp defines how the time of a record is rendered.
+	timestamp TimestampFormat
+
+	// Collisions defines how context values with reserved keys are handled.
+	collisions CollisionPolicy
+}
+
+// OtlpJsonFormatter will convert passed values to OpenTelemetry logs in OTLP/JSON encoding.
+type OtlpJsonFormatter struct {
+
+	// ServiceName is added as resource attribute service.name.
+	serviceName string
+
+	// Clock provides the observed time of records. System time is used if it's nil.
+	clock Clock
+}
+
+// MetricUnit is the unit of a metric, e.g. Milliseconds or Count.
+type MetricUnit string
+
+// Units supported by CloudWatch metrics.
+const (
+	UnitNone         MetricUnit = "None"
+	UnitSeconds      MetricUnit = "Seconds"
+	UnitMilliseconds MetricUnit = "Milliseconds"
+	UnitMicroseconds MetricUnit = "Microseconds"
+	UnitBytes        MetricUnit = "Bytes"
+	UnitKilobytes    MetricUnit = "Kilobytes"
+	UnitMegabytes    MetricUnit = "Megabytes"
+	UnitGigabytes    MetricUnit = "Gigabytes"
+	UnitBits         MetricUnit = "Bits"
+	UnitPercent      MetricUnit = "Percent"
+	UnitCount        MetricUnit = "Count"
+	UnitBytesPerSec  MetricUnit = "Bytes/Second"
+	UnitCountPerSec  MetricUnit = "Count/Second"
+)
+
+// Metric is a single value of a metric with its unit.
+type Metric struct {
+	Name  string
+	Value float64
+	Unit  MetricUnit
+}
+
+// EmfFormatter will convert passed values to CloudWatch Embedded Metric Format (EMF) documents.
+type EmfFormatter struct {
+
+	// Namespace is the CloudWatch namespace all metrics are published to.
+	namespace string
+
+	// Dimensions are context keys which are used as dimensions, if they're present in a record.
+	dimensions []string
+
+	// Timestamp defines how the time of a record is rendered.
+	timestamp TimestampFormat
+
+	// Collisions defines how context values with reserved keys are handled.
+	collisions CollisionPolicy
 }
 
 // StdoutShipper will print given log messages on stdout.
 type StdoutShipper struct {
 }
 
+// FileShipper writes log messages to a file, which is rotated by size and/or time.
+type FileShipper struct {
+
+	// Path of the log file.
+	path string
+
+	// MaxSize is the size in bytes a file is rotated at. Zero disables rotation by size.
+	maxSize int64
+
+	// Interval is the time after a file is rotated. Zero disables rotation by time.
+	interval time.Duration
+
+	// MaxBackups is the number of rotated files which are kept. Zero keeps all rotated files.
+	maxBackups int
+
+	// Compress enables gzip compression of rotated files.
+	compress bool
+
+	// File is the current log file, it's opened with the first record.
+	file *os.File
+
+	// Size is the current size of the log file.
+	size int64
+
+	// OpenedAt is the point in time the current log file has been opened.
+	openedAt time.Time
+
+	// Clock provides current time. System time is used if it's nil.
+	clock Clock
+
+	// Signals receives SIGHUP to reopen the log file.
+	signals chan os.Signal
+
+	// StopSignals ensures signal handling is stopped only once.
+	stopSignals sync.Once
+
+	// Compressions is the number of rotated files which are compressed and removed at the moment.
+	// It's protected by mutex.
+	compressions int
+
+	// Compressed is signaled each time compression of a rotated file has been finished.
+	compressed *sync.Cond
+
+	// BackupMutex ensures rotated files are compressed and removed by one goroutine at a time.
+	backupMutex sync.Mutex
+
+	// Mutex protects the log file.
+	mutex sync.Mutex
+}
+
+// SyslogShipper sends log messages to a syslog daemon over UDP, TCP, TLS or a unix socket.
+type SyslogShipper struct {
+
+	// Network is one of udp, tcp, tcp+tls or unix.
+	network string
+
+	// Address of the syslog daemon, e.g. localhost:514 or /dev/log.
+	address string
+
+	// TlsConfig is used for network tcp+tls.
+	tlsConfig *tls.Config
+
+	// Facility is used to add a priority to messages without one.
+	facility int
+
+	// Conn is the current connection to the syslog daemon. It's nil until the first message
+	// is sent and after a failure, to reconnect with the next message.
+	conn net.Conn
+
+	// Buffer keeps messages which have not been sent, yet.
+	buffer [][]byte
+
+	// BufferSize is the max number of buffered messages, oldest messages are dropped first.
+	bufferSize int
+
+	// Dropped counts messages dropped from a full buffer.
+	dropped int
+
+	// Timeout is used to connect and to write messages.
+	timeout time.Duration
+
+	// RetryInterval is the time the background sender waits after a failure before it tries again.
+	retryInterval time.Duration
+
+	// Pending notifies the background sender about new messages.
+	pending chan struct{}
+
+	// Done stops the background sender.
+	done chan struct{}
+
+	// Stop ensures the background sender is stopped only once.
+	stop sync.Once
+
+	// Mutex protects the buffer.
+	mutex sync.Mutex
+
+	// SendMutex protects the connection, so messages are sent by one goroutine at a time.
+	sendMutex sync.Mutex
+}
+
 // LogzioShipper will deliver log messages to Logz.io.
 type LogzioShipper struct {
 
+	// BatchShipper buffers records and ships them in batches to Logz.io.
+	batchShipper
+
 	// LogzioUrl is the enpooint all logs will be shipped to.
 	logzioUrl string
 
+	// TokenKey is the key used to obtain the Logz.io token from secrets manager.
+	tokenKey string
+
+	// HttpClient is used to send POST request to ship log messages.
+	httpClient httpClient
+
+	// SecretsManager is used to obtain Logz.io token for shipment requests.
+	secretsManager secrets.SecretsManager
+}
+
+// HttpShipper sends log messages in batches to a HTTP endpoint.
+type HttpShipper struct {
+
+	// BatchShipper buffers records and ships them in batches to the endpoint.
+	batchShipper
+
+	// Url of the endpoint all logs will be shipped to.
+	url string
+
+	// Method is the HTTP method used for shipment requests, e.g. POST.
+	method string
+
+	// Headers are added to each request.
+	headers map[string]string
+
+	// SecretHeaders are added to each request with a value obtained from secrets manager.
+	// Values of this map are keys of secrets.
+	secretHeaders map[string]string
+
+	// Framing is the format of request bodies, ndjson or json.
+	framing string
+
+	// SuccessCodes are status codes of successful requests. All 2xx status codes are accepted if it's empty.
+	successCodes []int
+
+	// HttpClient is used to send shipment requests.
+	httpClient httpClient
+
+	// SecretsManager is used to obtain values of secret headers.
+	secretsManager secrets.SecretsManager
+}
+
+// batchShipper buffers records and ships them in batches with a limited number of parallel shipments.
+type batchShipper struct {
+
 	// BatchSize defines the number of logs shipped together in a batch.
 	batchSize int
 
 	// ShipmentStack is a worker queue to restrict parallel shipment.
 	shipmentStack chan bool
 
-	// MessageStack is a channel to buffer log messages.
-	messageStack chan string
+	// MessageStack is a channel to buffer log records.
+	messageStack chan Record
 
 	// ObtainShipmentTimeout defines the time the shipper will wait to get
 	// a slot from shipmentStack.
@@ -85,9 +550,129 @@ type LogzioShipper struct {
 	// during reading from messageStack.
 	messageReadTimeout time.Duration
 
-	// HttpClient is used to send POST request to ship log messages.
-	httpClient httpClient
+	// ErrorHandler is called with records of each batch which could not be shipped.
+	errorHandler func(records []Record, err error)
 
-	// SecretsManager is used to obtain Logz.io token for shipment requests.
-	secretsManager secrets.SecretsManager
+	// Send ships a batch of records.
+	send func(records []Record) error
+}
+
+// SlogHandler is a slog.Handler which uses formatters and shippers of this package
+// to process log records created by log/slog.
+type SlogHandler struct {
+
+	// LogLevel is the minimum level of records which will be handled.
+	logLevel LogLevel
+
+	// Context contains all attributes added by WithAttrs as fields.
+	context LogContext
+
+	// Groups is the list of groups opened by WithGroup. They're used as
+	// prefix for keys of all subsequent attributes.
+	groups []string
+
+	// Formatter converts records into log messages.
+	formatter LogFormatter
+
+	// Shipper delivers log messages.
+	shipper LogShipper
+}
+
+// Assert SlogHandler implements slog.Handler.
+var _ slog.Handler = (*SlogHandler)(nil)
+
+// SlogLogger implements the Logger interface on top of a slog.Handler.
+type SlogLogger struct {
+
+	// Handler processes all log records.
+	handler slog.Handler
+
+	// Ctx is passed to the handler for each log record.
+	ctx context.Context
+
+	// Namespace is added as attribute to all log records if it's not empty.
+	namespace string
+
+	// Clock provides the time of log records. System time is used if it's nil.
+	clock Clock
+}
+
+// Assert SlogLogger implements Logger.
+var _ Logger = (*SlogLogger)(nil)
+
+// LogOutput is a combination of a formatter and a shipper which accepts
+// records up to a defined log level.
+type LogOutput struct {
+
+	// LogLevel is the most verbose log level records are passed to this output.
+	LogLevel LogLevel
+
+	// Formatter is used to create the payload of a record for this output.
+	// If it's nil, the payload created by the logger is used.
+	Formatter LogFormatter
+
+	// Shipper delivers records of this output.
+	Shipper LogShipper
+}
+
+// MultiShipper dispatches each record to several outputs.
+type MultiShipper struct {
+
+	// Outputs all records are dispatched to.
+	outputs []LogOutput
+}
+
+// LogRoute defines which records are forwarded to a sink of a RouterShipper.
+// All defined conditions have to match, empty conditions match all records.
+type LogRoute struct {
+
+	// Sink is the name of the sink matching records are forwarded to.
+	Sink string
+
+	// LogLevels is a list of log levels a record can have.
+	LogLevels []LogLevel
+
+	// Namespace matches records with this namespace or one of it's sub namespaces.
+	Namespace string
+
+	// Context is a list of context values or fields a record must have.
+	Context map[string]string
+}
+
+// RouterShipper forwards records to named sinks depending on ordered routes.
+type RouterShipper struct {
+
+	// Sinks records can be forwarded to.
+	sinks map[string]LogOutput
+
+	// Routes are evaluated in order, the first matching route defines the sink of a record.
+	routes []LogRoute
+
+	// DefaultSink is used for all records without a matching route.
+	defaultSink string
+}
+
+// FallbackShipper passes records to a secondary shipper if the primary shipper fails.
+type FallbackShipper struct {
+
+	// Primary is the shipper used as long as it works.
+	primary LogShipper
+
+	// Secondary gets all records the primary shipper failed to ship
+	// and all records during cool down.
+	secondary LogShipper
+
+	// CoolDown is the time records are passed to the secondary shipper
+	// after a failure before the primary shipper is retried.
+	coolDown time.Duration
+
+	// FailedAt is the point in time the primary shipper failed at last.
+	// It's zero as long as the primary shipper works.
+	failedAt time.Time
+
+	// Clock provides current time. System time is used if it's nil.
+	clock Clock
+
+	// Mutex protects failedAt.
+	mutex sync.Mutex
 }
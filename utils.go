@@ -2,14 +2,19 @@ package log
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
 
 	"github.com/aws/aws-lambda-go/lambdacontext"
 )
 
-// WithNameSpace appends passed namespace as log context
+// WithNameSpace appends passed namespace as log context. Passed logger is changed and returned.
+//
+// Deprecated: Use Named of a logger, which returns a child logger and doesn't change passed logger.
+// Unlike WithNameSpace, Named appends passed name to an existing namespace.
 func WithNameSpace(logger Logger, namespace string) Logger {
-	return AppendContextValues(logger, map[string]string{LogCtxNamespace: namespace})
+	return appendContextValues(logger, map[string]string{LogCtxNamespace: namespace})
 }
 
 // WithK8sContext appends kubernetes values from environment variables as context
@@ -25,22 +30,155 @@ func WithK8sContext(logger Logger) Logger {
 	if pod, ok := os.LookupEnv("K8S_POD_NAME"); ok {
 		logContextValues[LogCtxK8sPod] = pod
 	}
-	return AppendContextValues(logger, logContextValues)
+	return appendContextValues(logger, logContextValues)
 }
 
-// appendContextValues adds passed values to context fo given logger.
+// AppendContextValues adds passed values to context of given logger. Passed logger is changed and returned.
+//
+// Deprecated: Use With of a logger, which returns a child logger and doesn't change passed logger.
 func AppendContextValues(logger Logger, values map[string]string) Logger {
+	return appendContextValues(logger, values)
+}
+
+// appendContextValues adds passed values to context of given logger.
+// This works for loggers created by NewLogger or NewLoggerFromConfig only.
+func appendContextValues(logger Logger, values map[string]string) Logger {
+	if logHandler, ok := logger.(*LogHandler); ok {
+		logHandler.context = logHandler.context.withValues(values)
+	}
+	return logger
+}
+
+// WithFields appends passed typed fields to log context of given logger. Passed logger is changed and returned.
+//
+// Deprecated: Use With of a logger, which returns a child logger and doesn't change passed logger.
+func WithFields(logger Logger, fields ...Field) Logger {
+	if logHandler, ok := logger.(*LogHandler); ok {
+		logHandler.context = logHandler.context.AppendFields(fields...)
+	}
+	return logger
+}
+
+// WithHooks returns a child logger which calls passed hooks for each record.
+// Hooks of passed logger are kept. This works for loggers created by NewLogger
+// or NewLoggerFromConfig only, for all other loggers passed logger is returned.
+func WithHooks(logger Logger, hooks ...LogHook) Logger {
+
 	if logHandler, ok := logger.(*LogHandler); ok {
-		logHandler.context.AppendValues(values)
+		childLogger := logHandler.withLogContext(logHandler.context)
+		childLogger.hooks = append(append([]LogHook{}, logHandler.hooks...), hooks...)
+		return childLogger
 	}
 	return logger
 }
 
+// WithClock returns a child logger which uses passed clock for timestamps of all records.
+// The clock is passed to all formatters as well, including formatters of outputs and sinks,
+// if they use current time themselves. Shippers keep their clock, e.g. for rotation of files.
+// This works for loggers created by NewLogger, NewLoggerFromConfig or NewLoggerFromSlogHandler only,
+// for all other loggers passed logger is returned.
+func WithClock(logger Logger, clock Clock) Logger {
+
+	switch typedLogger := logger.(type) {
+	case *LogHandler:
+		childLogger := typedLogger.withLogContext(typedLogger.context)
+		childLogger.clock = clock
+		childLogger.formatter = formatterWithClock(childLogger.formatter, clock)
+		childLogger.shipper = shipperWithClock(childLogger.shipper, clock)
+		return childLogger
+	case *SlogLogger:
+		childLogger := *typedLogger
+		childLogger.clock = clock
+		if slogHandler, ok := typedLogger.handler.(*SlogHandler); ok {
+			childHandler := *slogHandler
+			childHandler.formatter = formatterWithClock(childHandler.formatter, clock)
+			childHandler.shipper = shipperWithClock(childHandler.shipper, clock)
+			childLogger.handler = &childHandler
+		}
+		return &childLogger
+	default:
+		return logger
+	}
+}
+
+// formatterWithClock returns a copy of passed formatter which uses given clock,
+// if it uses current time itself. Otherwise passed formatter is returned.
+func formatterWithClock(formatter LogFormatter, clock Clock) LogFormatter {
+	if clockFormatter, ok := formatter.(interface{ withClock(Clock) LogFormatter }); ok {
+		return clockFormatter.withClock(clock)
+	}
+	return formatter
+}
+
+// shipperWithClock returns a copy of a shipper with outputs or sinks, with formatters using passed clock.
+// Shippers of outputs and sinks are shared. All other shippers are returned as they are.
+func shipperWithClock(shipper LogShipper, clock Clock) LogShipper {
+
+	switch typedShipper := shipper.(type) {
+	case *MultiShipper:
+		outputs := make([]LogOutput, 0, len(typedShipper.outputs))
+		for _, output := range typedShipper.outputs {
+			output.Formatter = formatterWithClock(output.Formatter, clock)
+			outputs = append(outputs, output)
+		}
+		return &MultiShipper{outputs: outputs}
+	case *RouterShipper:
+		sinks := make(map[string]LogOutput, len(typedShipper.sinks))
+		for name, sink := range typedShipper.sinks {
+			sink.Formatter = formatterWithClock(sink.Formatter, clock)
+			sinks[name] = sink
+		}
+		return &RouterShipper{sinks: sinks, routes: typedShipper.routes, defaultSink: typedShipper.defaultSink}
+	default:
+		return shipper
+	}
+}
+
+// Close flushes passed logger and closes its shipper, to release resources like open files,
+// connections or signal handlers. Shippers of all outputs, sinks and fallbacks are closed as well.
+// A logger shouldn't be used after it has been closed. This works for loggers created by NewLogger,
+// NewLoggerFromConfig or NewLoggerFromSlogHandler only, all other loggers are flushed only.
+func Close(logger Logger) error {
+
+	logger.Flush()
+	switch typedLogger := logger.(type) {
+	case *LogHandler:
+		return closeShipper(typedLogger.shipper)
+	case *SlogLogger:
+		if slogHandler, ok := typedLogger.handler.(*SlogHandler); ok {
+			return closeShipper(slogHandler.shipper)
+		}
+	}
+	return nil
+}
+
+// closeShipper closes passed shipper if it has a Close method. For shippers with several
+// outputs all of them are closed.
+func closeShipper(shipper LogShipper) error {
+
+	var errs []error
+	switch typedShipper := shipper.(type) {
+	case *MultiShipper:
+		for _, output := range typedShipper.outputs {
+			errs = append(errs, closeShipper(output.Shipper))
+		}
+	case *RouterShipper:
+		for _, sink := range typedShipper.sinks {
+			errs = append(errs, closeShipper(sink.Shipper))
+		}
+	case *FallbackShipper:
+		errs = append(errs, closeShipper(typedShipper.primary), closeShipper(typedShipper.secondary))
+	case io.Closer:
+		errs = append(errs, typedShipper.Close())
+	}
+	return errors.Join(errs...)
+}
+
 // AppendFromLambdaContext appends some values from given context, e.g. a request id,
 // to current log context if passed context is a AWS Lambda context.
 func AppendFromLambdaContext(logger Logger, ctx context.Context) Logger {
 	if lambdaCtx, ok := lambdacontext.FromContext(ctx); ok {
-		return AppendContextValues(logger, map[string]string{LogCtxRequestId: lambdaCtx.AwsRequestID})
+		return appendContextValues(logger, map[string]string{LogCtxRequestId: lambdaCtx.AwsRequestID})
 	}
 	return logger
 }
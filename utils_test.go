@@ -61,6 +61,18 @@ func (suite *UtilsTestSuite) TestAppendContextValues() {
 	suite.Len(logHandler.context.values, 2)
 }
 
+func (suite *UtilsTestSuite) TestAppendContextValuesChangesLogger() {
+
+	logger := NewLogger(Debug, nil, nil)
+	childLogger := logger.With(String("key", "val"))
+	AppendContextValues(logger, map[string]string{"test": "val1"})
+	WithNameSpace(logger, "component1")
+
+	suite.Len(logger.(*LogHandler).context.values, 2)
+	suite.Equal("component1", logger.(*LogHandler).context.values[LogCtxNamespace])
+	suite.Len(childLogger.(*LogHandler).context.values, 0)
+}
+
 func (suite *UtilsTestSuite) TestAppendFromLambdaContext() {
 
 	logger := NewLogger(Debug, nil, nil)
@@ -80,3 +92,23 @@ func (suite *UtilsTestSuite) TestAppendFromLambdaContext() {
 	suite.True(ok2)
 	suite.Len(logHandler2.context.values, 0)
 }
+
+func (suite *UtilsTestSuite) TestClose() {
+
+	dir := suite.T().TempDir()
+	shipper1 := NewFileShipper(dir+"/app1.log", 0, 0, 0, false).(*FileShipper)
+	shipper2 := NewFileShipper(dir+"/app2.log", 0, 0, 0, false).(*FileShipper)
+	logger := NewLogger(Info, nil, NewMultiShipper(
+		LogOutput{LogLevel: Info, Formatter: newDefaultFormatter(), Shipper: shipper1},
+		LogOutput{LogLevel: Info, Formatter: newDefaultFormatter(), Shipper: NewFallbackShipper(shipper2, newStdoutShipper(), 0)},
+	))
+
+	logger.Info("message")
+	suite.NotNil(shipper1.file)
+	suite.NotNil(shipper2.file)
+	suite.Nil(Close(logger))
+	suite.Nil(shipper1.file)
+	suite.Nil(shipper2.file)
+
+	suite.Nil(Close(NewLogger(Info, nil, nil)))
+}